@@ -0,0 +1,262 @@
+package main
+
+import (
+    "encoding/json"
+    "os"
+)
+
+// Config bevat de instellingen uit config.json; ontbrekende velden houden hun standaardwaarde
+type Config struct {
+    // Tiebreaks na Punten, in volgorde: matchscore, ratopp, tpr, tpr_fide, rating
+    Tiebreaks []string `json:"tiebreaks"`
+    // Map met eigen templates en CSS; bestanden die daar ontbreken komen uit de meegeleverde templates
+    TemplateDir string `json:"templates"`
+    // Prijzenlijst met categorieën
+    Prizes PrizeConfig `json:"prizes"`
+    // Live scherm voor de projector
+    Live LiveConfig `json:"live"`
+    // Vaste borden per speler, bv. "Eva": 1 voor het gestreamde topbord
+    BoardPins map[string]int `json:"board_pins"`
+    // Ratingsysteem voor de overview en de nieuwe ratings
+    Rating RatingConfig `json:"rating"`
+    // Ratingdatabase die de ratings van toernooi naar toernooi meeneemt
+    Database DatabaseConfig `json:"database"`
+}
+
+// Bestand van de ratingdatabase en het ID waaronder dit toernooi wordt vastgelegd
+type DatabaseConfig struct {
+    File       string `json:"file"`       // Leeg = geen database
+    Tournament string `json:"tournament"` // Leeg = naam van de huidige map
+}
+
+// Keuze van het ratingsysteem, zie ratingSystems
+type RatingConfig struct {
+    System  string        `json:"system"`
+    Mode    string        `json:"mode"` // "batch" (alles aan het einde) of "per_round" (na elke ronde bijwerken)
+    Bonus   BonusConfig   `json:"bonus"`
+    Elo     EloConfig     `json:"elo"`
+    Glicko2 Glicko2Config `json:"glicko2"`
+    Margin  MarginConfig  `json:"margin"`
+    // Spelers zonder vaste rating (voorlopig=1 of rating 0 in input.txt)
+    Provisional ProvisionalConfig `json:"provisional"`
+}
+
+// Voorlopige ratings: schatting uit de performance en de weging van partijen tegen voorlopige spelers
+type ProvisionalConfig struct {
+    Estimate       string  `json:"estimate"`        // "tpr_fide" (FIDE dp-tabel) of "tpr" (lineair)
+    OpponentWeight float64 `json:"opponent_weight"` // Deel van de wijziging tegen een voorlopige speler: 1 = volledig, 0 = telt niet
+}
+
+// Ratingwijziging laten afhangen van het scoreverschil (bv. 6-0 telt zwaarder dan 4-3); werkt met elk systeem
+type MarginConfig struct {
+    Curve string  `json:"curve"` // "none" (uit), "linear", "sqrt" of "log"
+    Scale float64 `json:"scale"` // Factor = scale * curve(verschil), remises blijven ongewijzigd
+    Cap   float64 `json:"cap"`   // Maximale factor
+}
+
+// Instellingen voor Glicko-2; de standaardwaarden gelden voor spelers zonder rd= of vol= in input.txt
+type Glicko2Config struct {
+    Tau               float64 `json:"tau"` // Beperkt hoe snel de volatiliteit verandert, gebruikelijk 0.3 tot 1.2
+    DefaultRD         float64 `json:"default_rd"`
+    DefaultVolatility float64 `json:"default_volatility"`
+}
+
+// Parameters van de bonustabel (getBonus)
+type BonusConfig struct {
+    Range       int    `json:"range"`        // Ratingverschil waarbij de winst 0 of maximaal is
+    MaxGain     int    `json:"max_gain"`     // Maximale winst per partij
+    LossPercent int    `json:"loss_percent"` // Verlies = dit percentage van (max_gain - bonus)
+    DrawRule    string `json:"draw_rule"`    // "stronger_only", "symmetric" of "zero"
+    Rounding    string `json:"rounding"`     // "truncate" (na elke stap afkappen) of "round"
+}
+
+// Instellingen voor Elo: standaard K-factor en regels die een andere K geven
+type EloConfig struct {
+    K     float64 `json:"k"`
+    Rules []KRule `json:"k_rules"`
+}
+
+// Regel voor de K-factor; de eerste regel waarvan alle ingevulde voorwaarden kloppen geldt.
+// Voorwaarden op 0 doen niet mee; age_below geldt alleen voor spelers met een bekende leeftijd.
+type KRule struct {
+    K             float64 `json:"k"`
+    RatingBelow   int     `json:"rating_below"`
+    RatingAtLeast int     `json:"rating_at_least"`
+    GamesBelow    int     `json:"games_below"` // Partijen vóór dit toernooi (partijen= in input.txt)
+    AgeBelow      int     `json:"age_below"`
+    LevelBelow    int     `json:"level_below"`
+}
+
+// Instellingen van het live scherm (map live/)
+type LiveConfig struct {
+    Seconds     int `json:"refresh_seconds"`        // Seconden per pagina
+    Rows        int `json:"rows_per_page"`          // Regels per pagina voor stand en pairings
+    PairingRows int `json:"pairings_rows_per_page"` // Regels per pagina in de grote pairinglijst
+}
+
+// Prijsinstellingen: categorieën in volgorde van toekenning
+type PrizeConfig struct {
+    Categories []PrizeCategory `json:"categories"`
+    // Elke speler wint hoogstens één prijs, in de eerste categorie waarin de speler in de prijzen valt
+    OnePrize bool `json:"one_prize_per_player"`
+    // Gelijke Punten: "tiebreak" (volgorde van sortPlayers) of "split" (bedragen van de gedeelde plaatsen samen delen)
+    Ties string `json:"ties"`
+    // Eigen vlaggen met hun spelers, bv. "jeugd": ["Eva", "Jan"]
+    Flags map[string][]string `json:"flags"`
+}
+
+// Eén prijscategorie; grenzen op 0 en een lege vlag betekenen geen beperking
+type PrizeCategory struct {
+    Name      string    `json:"name"`
+    Amounts   []float64 `json:"amounts"` // Bedrag per plaats binnen de categorie
+    LevelMin  int       `json:"level_min"`
+    LevelMax  int       `json:"level_max"`
+    RatingMin int       `json:"rating_min"` // Op de rating bij de start
+    RatingMax int       `json:"rating_max"`
+    Flag      string    `json:"flag"`
+}
+
+// K-factor regels als config.json geen k_rules heeft: K 10 vanaf 2400
+var defaultKRules = []KRule{{K: 10, RatingAtLeast: 2400}}
+
+// Geldige tiebreaks voor sortPlayers
+var validTiebreaks = map[string]bool{
+    "matchscore": true,
+    "ratopp":     true,
+    "tpr":        true,
+    "tpr_fide":   true,
+    "rating":     true,
+}
+
+// Actieve configuratie, ingelezen bij het opstarten
+var config = defaultConfig()
+
+func defaultConfig() Config {
+    return Config{
+        Tiebreaks: []string{"matchscore", "ratopp", "rating"},
+        Prizes: PrizeConfig{
+            OnePrize: true,
+            Ties:     "tiebreak",
+        },
+        Live: LiveConfig{
+            Seconds:     15,
+            Rows:        20,
+            PairingRows: 10,
+        },
+        Rating: RatingConfig{
+            System: "bonus",
+            Mode:   "batch",
+            Bonus: BonusConfig{
+                Range:       675,
+                MaxGain:     40,
+                LossPercent: 75,
+                DrawRule:    "stronger_only",
+                Rounding:    "truncate",
+            },
+            Elo: EloConfig{
+                K: 20, // Zonder k_rules gelden defaultKRules
+            },
+            Glicko2: Glicko2Config{
+                Tau:               0.5,
+                DefaultRD:         350,
+                DefaultVolatility: 0.06,
+            },
+            Margin: MarginConfig{
+                Curve: "none",
+                Scale: 1,
+                Cap:   2,
+            },
+            Provisional: ProvisionalConfig{
+                Estimate:       "tpr_fide",
+                OpponentWeight: 1,
+            },
+        },
+        Database: DatabaseConfig{
+            File: "ratings.json",
+        },
+    }
+}
+
+// Configuratie inlezen; zonder config.json gelden de standaardwaarden
+func loadConfig(filename string) (Config, error) {
+    cfg := defaultConfig()
+    data, err := os.ReadFile(filename)
+    if os.IsNotExist(err) {
+        return cfg, nil
+    } else if err != nil {
+        return cfg, err
+    }
+    if err := json.Unmarshal(data, &cfg); err != nil {
+        return cfg, err
+    }
+    for _, tiebreak := range cfg.Tiebreaks {
+        if !validTiebreaks[tiebreak] {
+            return cfg, errorT("fout.onbekende_tiebreak", tiebreak)
+        }
+    }
+    if cfg.Prizes.Ties != "tiebreak" && cfg.Prizes.Ties != "split" {
+        return cfg, errorT("fout.onbekende_gelijke_stand", cfg.Prizes.Ties)
+    }
+    for _, category := range cfg.Prizes.Categories {
+        if _, ok := cfg.Prizes.Flags[category.Flag]; category.Flag != "" && !ok {
+            return cfg, errorT("fout.onbekende_vlag", category.Name, category.Flag)
+        }
+    }
+    if _, ok := ratingSystems[cfg.Rating.System]; !ok {
+        return cfg, errorT("fout.onbekend_ratingsysteem", cfg.Rating.System)
+    }
+    // Pas na het inlezen invullen: json zou de velden van bestaande regels anders samenvoegen met de nieuwe
+    if cfg.Rating.Elo.Rules == nil {
+        cfg.Rating.Elo.Rules = defaultKRules
+    }
+    if b := cfg.Rating.Bonus; b.Range <= 0 || b.MaxGain <= 0 || 2*b.Range < b.MaxGain || b.LossPercent < 0 {
+        return cfg, errorT("fout.bonus_waarden")
+    }
+    if d := cfg.Rating.Bonus.DrawRule; d != "stronger_only" && d != "symmetric" && d != "zero" {
+        return cfg, errorT("fout.bonus_keuze", "draw_rule", d)
+    }
+    if r := cfg.Rating.Bonus.Rounding; r != "truncate" && r != "round" {
+        return cfg, errorT("fout.bonus_keuze", "rounding", r)
+    }
+    if cfg.Rating.Elo.K <= 0 {
+        return cfg, errorT("fout.elo_k")
+    }
+    for _, rule := range cfg.Rating.Elo.Rules {
+        if rule.K <= 0 {
+            return cfg, errorT("fout.elo_k")
+        }
+    }
+    if g := cfg.Rating.Glicko2; g.Tau <= 0 || g.DefaultRD <= 0 || g.DefaultVolatility <= 0 {
+        return cfg, errorT("fout.glicko2")
+    }
+    if _, ok := marginCurves[cfg.Rating.Margin.Curve]; !ok && cfg.Rating.Margin.Curve != "none" {
+        return cfg, errorT("fout.marge_curve", cfg.Rating.Margin.Curve)
+    }
+    if m := cfg.Rating.Margin; m.Scale <= 0 || m.Cap <= 0 {
+        return cfg, errorT("fout.marge_waarden")
+    }
+    if cfg.Rating.Mode != "batch" && cfg.Rating.Mode != "per_round" {
+        return cfg, errorT("fout.rating_modus", cfg.Rating.Mode)
+    }
+    if e := cfg.Rating.Provisional.Estimate; e != "tpr_fide" && e != "tpr" {
+        return cfg, errorT("fout.voorlopig_schatting", e)
+    }
+    if w := cfg.Rating.Provisional.OpponentWeight; w < 0 || w > 1 {
+        return cfg, errorT("fout.voorlopig_weging")
+    }
+    for name, board := range cfg.BoardPins {
+        if board < 1 {
+            return cfg, errorT("fout.bord_pin", board, name)
+        }
+    }
+    for key, value := range map[string]int{
+        "refresh_seconds":        cfg.Live.Seconds,
+        "rows_per_page":          cfg.Live.Rows,
+        "pairings_rows_per_page": cfg.Live.PairingRows,
+    } {
+        if value < 1 {
+            return cfg, errorT("fout.live_instelling", key)
+        }
+    }
+    return cfg, nil
+}
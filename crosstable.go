@@ -0,0 +1,181 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "strings"
+    "text/tabwriter"
+)
+
+// Eén vakje in de crosstable: tegenstander, kleur en uitslag in een ronde
+type CrossCell struct {
+    Round    int    `json:"ronde"`
+    Opponent string `json:"tegenstander,omitempty"`
+    OppRank  int    `json:"rank_tegenstander,omitempty"` // Eindrank van de tegenstander, 0 bij bye of niet gespeeld
+    Seat     int    `json:"kleur,omitempty"`             // 1 = eerste speler (wit), 2 = tweede speler (zwart)
+    Score    string `json:"score,omitempty"`             // Score vanuit de speler gezien, bv "6-2"
+    Outcome  string `json:"resultaat,omitempty"`         // "w", "d", "l", "bye" of "" als de speler niet speelde
+}
+
+// Korte notatie zoals "5w+" (rank 5, wit, gewonnen)
+func (c CrossCell) String() string {
+    switch c.Outcome {
+    case "":
+        return ""
+    case "bye":
+        return T("doc.bye_kort")
+    }
+    color := T("doc.kleur_wit")
+    if c.Seat == 2 {
+        color = T("doc.kleur_zwart")
+    }
+    symbol := map[string]string{"w": "+", "d": "=", "l": "-"}[c.Outcome]
+    return fmt.Sprintf("%d%s%s", c.OppRank, color, symbol)
+}
+
+// Waarde van één tiebreak van een speler
+type CrossTiebreak struct {
+    Tiebreak string  `json:"tiebreak"` // Naam uit config.Tiebreaks, bv. "tpr_fide"
+    Value    float64 `json:"waarde"`
+}
+
+// Waarde zoals in de tabel: RatOpp met twee decimalen, de rest afgerond
+func (tb CrossTiebreak) String() string {
+    if tb.Tiebreak == "ratopp" {
+        return fmt.Sprintf("%.2f", tb.Value)
+    }
+    return fmt.Sprintf("%.0f", tb.Value)
+}
+
+// Eén rij in de crosstable, in volgorde van de eindstand
+type CrossRow struct {
+    Rank      int             `json:"rank"`
+    Name      string          `json:"naam"`
+    Level     int             `json:"level"`
+    Rating    int             `json:"rating"`
+    Cells     []CrossCell     `json:"rondes"`
+    Punten    int             `json:"punten"`
+    Tiebreaks []CrossTiebreak `json:"tiebreaks"` // In de volgorde van config.Tiebreaks, zoals sortPlayers ze gebruikt
+}
+
+// Crosstable opbouwen uit de beginspelers en de uitslagen van alle rondes
+func buildCrosstable(initial []Player, allResults [][]Result) []CrossRow {
+    final := resetPlayers(initial)
+    sortPlayers(final)
+    if standings := replayRounds(initial, allResults); len(standings) > 0 {
+        final = standings[len(standings)-1]
+    }
+    rank := make(map[string]int)
+    for i, p := range final {
+        rank[p.Name] = i + 1
+    }
+
+    var rows []CrossRow
+    for i, p := range final {
+        row := CrossRow{
+            Rank:   i + 1,
+            Name:   p.Name,
+            Level:  p.Level,
+            Rating: startRating(p), // Bij "per_round" is p.Rating de rating na de laatste ronde
+            Punten: p.Punten,
+        }
+        for _, tiebreak := range config.Tiebreaks {
+            row.Tiebreaks = append(row.Tiebreaks, CrossTiebreak{Tiebreak: tiebreak, Value: tiebreakValue(p, tiebreak)})
+        }
+        for r, results := range allResults {
+            cell := CrossCell{Round: r + 1}
+            for _, result := range results {
+                if result.Player1 == p.Name {
+                    cell.Opponent = result.Player2
+                    cell.Seat = 1
+                    cell.Score = fmt.Sprintf("%d-%d", result.Score1, result.Score2)
+                } else if result.Player2 == p.Name {
+                    cell.Opponent = result.Player1
+                    cell.Seat = 2
+                    cell.Score = fmt.Sprintf("%d-%d", result.Score2, result.Score1)
+                } else {
+                    continue
+                }
+                if cell.Opponent == "Bye" {
+                    cell.Outcome = "bye"
+                } else {
+                    cell.Outcome = getMatchOutcome(p.Name, result)
+                    cell.OppRank = rank[cell.Opponent]
+                }
+                break
+            }
+            row.Cells = append(row.Cells, cell)
+        }
+        rows = append(rows, row)
+    }
+    return rows
+}
+
+// Crosstable wegschrijven als crosstable.html, crosstable.txt en crosstable.json
+func generateCrosstable(rows []CrossRow, rounds int) error {
+    if err := generateCrosstableHTML("crosstable.html", rows, rounds); err != nil {
+        return err
+    }
+    if err := generateCrosstableText("crosstable.txt", rows, rounds); err != nil {
+        return err
+    }
+    data, err := json.MarshalIndent(rows, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile("crosstable.json", data, 0644)
+}
+
+func generateCrosstableHTML(filename string, rows []CrossRow, rounds int) error {
+    t, err := loadTemplate(nil, "crosstable.html")
+    if err != nil {
+        return err
+    }
+
+    file, err := os.Create(filename)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    var roundNrs []int
+    for r := 1; r <= rounds; r++ {
+        roundNrs = append(roundNrs, r)
+    }
+    data := struct {
+        Rounds    []int
+        Tiebreaks []string
+        Rows      []CrossRow
+    }{Rounds: roundNrs, Tiebreaks: tiebreakLabels(), Rows: rows}
+    return t.Execute(file, data)
+}
+
+func generateCrosstableText(filename string, rows []CrossRow, rounds int) error {
+    file, err := os.Create(filename)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    w := tabwriter.NewWriter(file, 0, 0, 2, ' ', 0)
+    header := []string{T("doc.nr"), T("doc.naam"), T("doc.rating")}
+    for r := 1; r <= rounds; r++ {
+        header = append(header, T("doc.ronde_kort", r))
+    }
+    header = append(header, T("doc.punten"))
+    header = append(header, tiebreakLabels()...)
+    fmt.Fprintln(w, strings.Join(header, "\t"))
+    for _, row := range rows {
+        fields := []string{fmt.Sprint(row.Rank), row.Name, fmt.Sprint(row.Rating)}
+        for _, cell := range row.Cells {
+            fields = append(fields, cell.String())
+        }
+        fields = append(fields, fmt.Sprint(row.Punten))
+        for _, tb := range row.Tiebreaks {
+            fields = append(fields, tb.String())
+        }
+        fmt.Fprintln(w, strings.Join(fields, "\t"))
+    }
+    return w.Flush()
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+    "fmt"
+    "html"
+    "html/template"
+    "math"
+    "strings"
+)
+
+// Eén lijn in een grafiek: een waarde per ronde
+type chartSeries struct {
+    Name   string
+    Values []float64
+}
+
+// Afmetingen van de grafieken in pixels
+const (
+    chartWidth  = 760
+    chartHeight = 360
+    chartLeft   = 45
+    chartRight  = 170 // Ruimte voor de legenda
+    chartTop    = 30
+    chartBottom = 35
+)
+
+// Lijngrafiek met een punt per ronde
+func lineChartSVG(title string, series []chartSeries, invert bool) template.HTML {
+    if len(series) == 0 {
+        return ""
+    }
+    var labels, points []string
+    for r := range series[0].Values {
+        labels = append(labels, T("doc.ronde_kort", r+1))
+        points = append(points, T("doc.ronde_punt", r+1))
+    }
+    return labeledChartSVG(title, labels, points, series, invert)
+}
+
+// Lijngrafiek als inline SVG, zonder JavaScript. labels staan onder de x-as, points in de tooltip van elk punt.
+// Met invert staat de laagste waarde bovenaan (voor plaatsen).
+func labeledChartSVG(title string, labels []string, points []string, series []chartSeries, invert bool) template.HTML {
+    if len(series) == 0 || len(series[0].Values) == 0 {
+        return ""
+    }
+    rounds := len(series[0].Values)
+    height := chartHeight
+    if legend := chartTop + len(series)*14 + 10; legend > height {
+        height = legend
+    }
+    plotW := float64(chartWidth - chartLeft - chartRight)
+    plotH := float64(height - chartTop - chartBottom)
+
+    minV, maxV := math.Inf(1), math.Inf(-1)
+    for _, s := range series {
+        for _, v := range s.Values {
+            minV = math.Min(minV, v)
+            maxV = math.Max(maxV, v)
+        }
+    }
+    if minV == maxV {
+        minV--
+        maxV++
+    }
+
+    x := func(round int) float64 {
+        if rounds == 1 {
+            return chartLeft + plotW/2
+        }
+        return chartLeft + plotW*float64(round)/float64(rounds-1)
+    }
+    y := func(v float64) float64 {
+        frac := (v - minV) / (maxV - minV)
+        if invert {
+            return chartTop + plotH*frac
+        }
+        return chartTop + plotH*(1-frac)
+    }
+
+    var sb strings.Builder
+    fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`,
+        chartWidth, height, chartWidth, height)
+    fmt.Fprintf(&sb, `<text x="%d" y="18" font-size="14" font-weight="bold">%s</text>`, chartLeft, html.EscapeString(title))
+
+    // Assen en rasterlijnen
+    fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%.0f" height="%.0f" fill="none" stroke="lightgray"/>`, chartLeft, chartTop, plotW, plotH)
+    for r := 0; r < rounds; r++ {
+        fmt.Fprintf(&sb, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%.0f" stroke="#eee"/>`, x(r), chartTop, x(r), chartTop+plotH)
+        fmt.Fprintf(&sb, `<text x="%.1f" y="%.0f" text-anchor="middle">%s</text>`, x(r), chartTop+plotH+15, html.EscapeString(labels[r]))
+    }
+    step := math.Max(1, math.Ceil((maxV-minV)/10))
+    for v := math.Ceil(minV); v <= maxV; v += step {
+        fmt.Fprintf(&sb, `<line x1="%d" y1="%.1f" x2="%.0f" y2="%.1f" stroke="#eee"/>`, chartLeft, y(v), chartLeft+plotW, y(v))
+        fmt.Fprintf(&sb, `<text x="%d" y="%.1f" text-anchor="end">%g</text>`, chartLeft-5, y(v)+4, v)
+    }
+    if !invert && minV < 0 && maxV > 0 {
+        fmt.Fprintf(&sb, `<line x1="%d" y1="%.1f" x2="%.0f" y2="%.1f" stroke="gray"/>`, chartLeft, y(0), chartLeft+plotW, y(0))
+    }
+
+    // Lijnen, punten met tooltip en legenda
+    for i, s := range series {
+        color := fmt.Sprintf("hsl(%d,70%%,42%%)", i*360/len(series))
+        name := html.EscapeString(s.Name)
+        var points []string
+        for r, v := range s.Values {
+            points = append(points, fmt.Sprintf("%.1f,%.1f", x(r), y(v)))
+        }
+        fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(points, " "), color)
+        for r, v := range s.Values {
+            fmt.Fprintf(&sb, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s</title></circle>`,
+                x(r), y(v), color, html.EscapeString(T("doc.grafiek_punt", s.Name, points[r], v)))
+        }
+        ly := chartTop + i*14 + 5
+        fmt.Fprintf(&sb, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="3"/>`,
+            chartWidth-chartRight+15, ly, chartWidth-chartRight+35, ly, color)
+        fmt.Fprintf(&sb, `<text x="%d" y="%d">%s</text>`, chartWidth-chartRight+40, ly+4, name)
+    }
+    sb.WriteString(`</svg>`)
+    return template.HTML(sb.String())
+}
+
+// Plaats en cumulatieve Matchscore per speler na elke ronde, in volgorde van de laatste stand
+func progressionSeries(standings [][]Player) (ranks []chartSeries, matchscores []chartSeries) {
+    if len(standings) == 0 {
+        return nil, nil
+    }
+    for _, p := range standings[len(standings)-1] {
+        rank := chartSeries{Name: p.Name}
+        matchscore := chartSeries{Name: p.Name}
+        for _, standing := range standings {
+            for i, q := range standing {
+                if q.Name == p.Name {
+                    rank.Values = append(rank.Values, float64(i+1))
+                    matchscore.Values = append(matchscore.Values, float64(q.Matchscore))
+                    break
+                }
+            }
+        }
+        ranks = append(ranks, rank)
+        matchscores = append(matchscores, matchscore)
+    }
+    return ranks, matchscores
+}
+
+// Beide grafieken (plaats en Matchscore) voor een reeks standen
+func progressionCharts(standings [][]Player) []template.HTML {
+    ranks, matchscores := progressionSeries(standings)
+    if len(ranks) == 0 {
+        return nil
+    }
+    return []template.HTML{
+        lineChartSVG(T("doc.grafiek_plaats"), ranks, true),
+        lineChartSVG(T("doc.grafiek_matchscore"), matchscores, false),
+    }
+}
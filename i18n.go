@@ -0,0 +1,78 @@
+package main
+
+import (
+    "embed"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// Berichtencatalogi per taal (lang/nl.json, lang/en.json, ...); Nederlands is de standaard
+//go:embed lang/*.json
+var catalogFiles embed.FS
+
+const defaultLanguage = "nl"
+
+// Actieve catalogus en de Nederlandse catalogus als terugval voor ontbrekende vertalingen
+var (
+    catalog         map[string]string
+    fallbackCatalog map[string]string
+)
+
+func readCatalog(lang string) (map[string]string, error) {
+    data, err := catalogFiles.ReadFile("lang/" + lang + ".json")
+    if err != nil {
+        return nil, err
+    }
+    messages := make(map[string]string)
+    err = json.Unmarshal(data, &messages)
+    return messages, err
+}
+
+// Beschikbare talen, afgeleid van de catalogusbestanden
+func availableLanguages() []string {
+    entries, _ := catalogFiles.ReadDir("lang")
+    var langs []string
+    for _, entry := range entries {
+        langs = append(langs, strings.TrimSuffix(entry.Name(), ".json"))
+    }
+    sort.Strings(langs)
+    return langs
+}
+
+// Taal kiezen voor het menu, de meldingen en alle gegenereerde documenten
+func setLanguage(lang string) error {
+    var err error
+    if fallbackCatalog == nil {
+        if fallbackCatalog, err = readCatalog(defaultLanguage); err != nil {
+            return err
+        }
+    }
+    messages, err := readCatalog(lang)
+    if err != nil {
+        return errorT("fout.onbekende_taal", lang, strings.Join(availableLanguages(), ", "))
+    }
+    catalog = messages
+    return nil
+}
+
+// Bericht opzoeken in de actieve taal en invullen met de argumenten
+func T(key string, args ...interface{}) string {
+    msg, ok := catalog[key]
+    if !ok {
+        if msg, ok = fallbackCatalog[key]; !ok {
+            msg = key
+        }
+    }
+    if len(args) == 0 {
+        return msg
+    }
+    return fmt.Sprintf(msg, args...)
+}
+
+// Foutmelding in de actieve taal
+func errorT(key string, args ...interface{}) error {
+    return errors.New(T(key, args...))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "text/tabwriter"
+)
+
+// Wat er voor één speler op het bord op het spel staat
+type Stake struct {
+    Expected    float64 // Verwachte score volgens het ratingsysteem (0 tot 1)
+    Win         int     // Ratingwijziging bij winst, remise en verlies
+    Draw        int
+    Loss        int
+    Provisional bool    // Voorlopige speler: de nieuwe rating wordt uit de performance geschat
+}
+
+// Pairing met de inzet voor beide spelers
+type StakedMatch struct {
+    Match
+    Stake1 Stake
+    Stake2 Stake
+}
+
+// Inzet van een speler tegen een tegenstander met het actieve ratingsysteem, als losse partij.
+// Bij weging op het scoreverschil geldt een verschil van 1 punt.
+func playerStake(player Player, opponent Player, system RatingSystem) Stake {
+    stake := Stake{Provisional: player.Provisional}
+    for _, outcome := range []string{"w", "d", "l"} {
+        score, opponentScore := 1, 0
+        switch outcome {
+        case "d":
+            score = 0
+        case "l":
+            score, opponentScore = 0, 1
+        }
+        games := []RatedGame{{Opponent: opponent, OpponentRating: opponent.Rating, Outcome: outcome, Score: score, OpponentScore: opponentScore}}
+        change := system.Rate(player, player.Rating, games)
+        weighProvisionalOpponents(&change, games)
+        stake.Expected = change.Games[0].Expected
+        switch outcome {
+        case "w":
+            stake.Win = change.Total
+        case "d":
+            stake.Draw = change.Total
+        case "l":
+            stake.Loss = change.Total
+        }
+    }
+    return stake
+}
+
+// Ratingstand bij de start van een ronde. Bij "per_round" zijn rating, partijen, deviatie en volatiliteit
+// dan al bijgewerkt door de vorige rondes, zodat de inzet klopt met wat ratePeriod straks afrekent.
+func stakeState(players []Player, allResults [][]Result, round int) map[string]Player {
+    if len(allResults) > round-1 {
+        allResults = allResults[:round-1]
+    }
+    return currentRatingState(players, allResults)
+}
+
+// Speler zoals hij in de ratingstand staat; spelers die er niet in staan blijven zoals ze zijn
+func statePlayer(state map[string]Player, p Player) Player {
+    if s, ok := state[p.Name]; ok {
+        return s
+    }
+    return p
+}
+
+// Inzet per bord tegen de ratingstand van stakeState; byes krijgen geen inzet
+func stakeMatches(matches []Match, state map[string]Player) []StakedMatch {
+    system := activeRatingSystem()
+    var staked []StakedMatch
+    for _, match := range matches {
+        sm := StakedMatch{Match: match}
+        if match.Player2.Name != "Bye" {
+            p1, p2 := statePlayer(state, match.Player1), statePlayer(state, match.Player2)
+            sm.Stake1 = playerStake(p1, p2, system)
+            sm.Stake2 = playerStake(p2, p1, system)
+        }
+        staked = append(staked, sm)
+    }
+    return staked
+}
+
+// Inzet als tekst: winst / remise / verlies, of de melding voor een voorlopige speler
+func (s Stake) String() string {
+    if s.Provisional {
+        return T("doc.inzet_voorlopig")
+    }
+    return fmt.Sprintf("%+d / %+d / %+d", s.Win, s.Draw, s.Loss)
+}
+
+// RondeX_inzet.txt: per bord de verwachte score en de ratingwijziging bij winst, remise en verlies
+func generateStakesText(round int, players []Player, matches []Match, allResults [][]Result) error {
+    sortByBoard(matches)
+    file, err := os.Create(fmt.Sprintf("ronde%d_inzet.txt", round))
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    fmt.Fprintln(file, T("doc.inzet_titel", round, activeRatingSystem().Name()))
+    w := tabwriter.NewWriter(file, 0, 0, 2, ' ', 0)
+    fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", T("doc.bord"),
+        T("doc.naam"), T("doc.rating"), T("doc.verwacht"), T("doc.inzet_kop"),
+        T("doc.naam"), T("doc.rating"), T("doc.verwacht"), T("doc.inzet_kop"))
+    for _, sm := range stakeMatches(matches, stakeState(players, allResults, round)) {
+        if sm.Player2.Name == "Bye" {
+            fmt.Fprintf(w, "%d\t%s\t%d\t\t\t%s\t\t\t\n", sm.Board, sm.Player1.Name, sm.Player1.Rating, T("doc.bye"))
+            continue
+        }
+        fmt.Fprintf(w, "%d\t%s\t%d\t%.0f%%\t%s\t%s\t%d\t%.0f%%\t%s\n", sm.Board,
+            sm.Player1.Name, sm.Player1.Rating, 100*sm.Stake1.Expected, sm.Stake1,
+            sm.Player2.Name, sm.Player2.Rating, 100*sm.Stake2.Expected, sm.Stake2)
+    }
+    return w.Flush()
+}
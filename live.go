@@ -0,0 +1,139 @@
+package main
+
+import (
+    "fmt"
+    "html/template"
+    "os"
+    "path/filepath"
+)
+
+// Eén pagina van het live scherm; elke pagina ververst zichzelf en gaat dan naar Next
+type livePage struct {
+    Round     int
+    Page      int
+    Pages     int
+    Next      string
+    Seconds   int
+    Offset    int // Aantal plaatsen op de vorige pagina's
+    StandNa   int // Ronde waarna de stand geldt
+    Standings []Player
+    Matches   []LiveMatch
+    Pairings  []PairingEntry
+}
+
+// Pairing op het live scherm; Played geeft aan of de score getoond wordt
+type LiveMatch struct {
+    Match
+    Played bool
+}
+
+// Heeft een bord een uitslag: net als bij roundPlayed tellen "-" (nog niet ingevuld) en 0-0 als niet gespeeld
+func matchPlayed(match Match) bool {
+    score1, score2, ok := parseScore(match.Result)
+    return ok && (score1 != 0 || score2 != 0)
+}
+
+// Lijst opdelen in stukken van hoogstens size regels
+func paginate(n int, size int) [][2]int {
+    var pages [][2]int
+    for start := 0; start < n; start += size {
+        end := start + size
+        if end > n {
+            end = n
+        }
+        pages = append(pages, [2]int{start, end})
+    }
+    return pages
+}
+
+// Bestandsnaam van pagina i (vanaf 0) in een reeks: naam.html, naam_2.html, ...
+func livePageName(base string, i int) string {
+    if i == 0 {
+        return base + ".html"
+    }
+    return fmt.Sprintf("%s_%d.html", base, i+1)
+}
+
+// Live scherm voor de projector bijwerken in dir:
+// index.html en volgende pagina's wisselen stand en pairings af, pairings.html toont de pairings op naam in grote letters.
+func generateLive(dir string, round int, players []Player, matches []Match) error {
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        return err
+    }
+    // Pagina's van een vorige keer opruimen zodat er geen verouderde pagina's blijven rondhangen
+    for _, pattern := range []string{"index_*.html", "pairings_*.html"} {
+        old, _ := filepath.Glob(filepath.Join(dir, pattern))
+        for _, file := range old {
+            os.Remove(file)
+        }
+    }
+    css, err := readTemplateFile("live.css")
+    if err != nil {
+        return err
+    }
+    if err := os.WriteFile(filepath.Join(dir, "live.css"), []byte(css), 0644); err != nil {
+        return err
+    }
+    t, err := loadTemplate(nil, "live.html")
+    if err != nil {
+        return err
+    }
+
+    standings := copyPlayers(players)
+    sortPlayers(standings)
+    sortByBoard(matches)
+
+    // Stand na de laatste gespeelde ronde; zolang de huidige ronde geen uitslagen heeft is dat de vorige
+    standNa := round - 1
+    var liveMatches []LiveMatch
+    for _, match := range matches {
+        if match.Player2.Name != "Bye" && matchPlayed(match) {
+            standNa = round
+        }
+        liveMatches = append(liveMatches, LiveMatch{Match: match, Played: matchPlayed(match)})
+    }
+
+    var screens []livePage
+    if standNa > 0 {
+        for _, p := range paginate(len(standings), config.Live.Rows) {
+            screens = append(screens, livePage{Offset: p[0], StandNa: standNa, Standings: standings[p[0]:p[1]]})
+        }
+    }
+    for _, p := range paginate(len(matches), config.Live.Rows) {
+        screens = append(screens, livePage{Matches: liveMatches[p[0]:p[1]]})
+    }
+    if err := writeLivePages(t, dir, "index", round, screens); err != nil {
+        return err
+    }
+
+    var boards []livePage
+    pairings := alphabeticalPairings(matches)
+    for _, p := range paginate(len(pairings), config.Live.PairingRows) {
+        boards = append(boards, livePage{Pairings: pairings[p[0]:p[1]]})
+    }
+    return writeLivePages(t, dir, "pairings", round, boards)
+}
+
+// Een reeks pagina's wegschrijven die in een lus naar elkaar doorverwijzen
+func writeLivePages(t *template.Template, dir string, base string, round int, pages []livePage) error {
+    if len(pages) == 0 {
+        pages = []livePage{{}} // Lege pagina, zodat het scherm blijft verversen tot er iets te tonen is
+    }
+    for i, page := range pages {
+        page.Round = round
+        page.Page = i + 1
+        page.Pages = len(pages)
+        page.Next = livePageName(base, (i+1)%len(pages))
+        page.Seconds = config.Live.Seconds
+        file, err := os.Create(filepath.Join(dir, livePageName(base, i)))
+        if err != nil {
+            return err
+        }
+        err = t.Execute(file, page)
+        file.Close()
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
@@ -2,12 +2,18 @@ package main
 
 import (
     "bufio"
+    "encoding/csv"
+    "flag"
     "fmt"
     "html/template"
+    "io"
+    "math"
     "os"
+    "path/filepath"
     "sort"
     "strconv"
     "strings"
+    "time"
 )
 
 // Player struct om een speler te vertegenwoordigen
@@ -20,6 +26,13 @@ type Player struct {
     Opponents    []string
     RatOppTotal  float64 // Totale som van ratings van tegenstanders
     RoundsPlayed int     // Aantal gespeelde rondes
+    Byes         int     // Aantal byes (tellen niet mee als partij)
+    Games        int     // Partijen gespeeld vóór dit toernooi (partijen=, voor de K-factor)
+    Age          int     // Leeftijd (leeftijd=), 0 = onbekend
+    RD           float64 // Glicko-2 ratingdeviatie (rd=), 0 = nog onbekend
+    Volatility   float64 // Glicko-2 volatiliteit (vol=), 0 = nog onbekend
+    Provisional  bool    // Nog geen vaste rating (voorlopig=1 of rating 0): nieuwe rating uit de performance
+    RoundRatings []int   // Rating waarmee in elke ronde gespeeld is; bij "per_round" verandert die per ronde
 }
 
 // Match struct voor een pairing
@@ -27,14 +40,16 @@ type Match struct {
     Player1 Player
     Player2 Player
     Result  string // bv "3-3"
+    Board   int    // Bordnummer, vast vanaf generateRoundFile
 }
 
 // Result struct voor scores uit rondeX.txt
 type Result struct {
-    Player1 string
-    Player2 string
-    Score1  int
-    Score2  int
+    Player1 string `json:"speler1"`
+    Player2 string `json:"speler2"`
+    Score1  int    `json:"score1"`
+    Score2  int    `json:"score2"`
+    Board   int    `json:"bord,omitempty"`
 }
 
 var byePlayer = Player{Name: "Bye", Level: 0, Rating: 0}
@@ -52,12 +67,12 @@ func readPlayers(filename string) ([]Player, error) {
     for scanner.Scan() {
         line := scanner.Text()
         parts := strings.Split(line, "   ") // Drie spaties
-        if len(parts) != 3 {
+        if len(parts) < 3 {
             continue
         }
         level, _ := strconv.Atoi(parts[1])
         rating, _ := strconv.Atoi(parts[2])
-        players = append(players, Player{
+        player := Player{
             Name:         parts[0],
             Level:        level,
             Rating:       rating,
@@ -66,45 +81,78 @@ func readPlayers(filename string) ([]Player, error) {
             Opponents:    []string{},
             RatOppTotal:  0.0,
             RoundsPlayed: 0,
-        })
+            Provisional:  rating == 0,
+        }
+        // Optionele velden na de rating, bv. "partijen=12   leeftijd=14"
+        for _, field := range parts[3:] {
+            if err := setPlayerField(&player, strings.TrimSpace(field)); err != nil {
+                return nil, err
+            }
+        }
+        players = append(players, player)
     }
     return players, scanner.Err()
 }
 
 // Matches laden uit rondeX.txt
 func loadMatches(filename string, players []Player) ([]Match, error) {
-    file, err := os.Open(filename)
+    lines, err := readRoundLines(filename)
     if err != nil {
         return nil, err
     }
-    defer file.Close()
 
     var matches []Match
-    scanner := bufio.NewScanner(file)
-    for scanner.Scan() {
-        line := scanner.Text()
-        parts := strings.Split(line, "   ")
-        if len(parts) != 3 {
-            continue
-        }
-        p1Name := strings.Split(parts[0], " LVL")[0]
-        p2Name := strings.Split(parts[2], " LVL")[0]
-        if p2Name == "Bye" {
-            p2Name = "Bye"
-        }
-        result := parts[1]
-
+    for _, rl := range lines {
         var p1, p2 Player
+        found1, found2 := false, rl.Name2 == "Bye"
+        if found2 {
+            p2 = byePlayer
+        }
         for _, player := range players {
-            if player.Name == p1Name {
+            if player.Name == rl.Name1 {
                 p1 = player
-            } else if player.Name == p2Name {
+                found1 = true
+            } else if player.Name == rl.Name2 {
                 p2 = player
+                found2 = true
             }
         }
-        matches = append(matches, Match{Player1: p1, Player2: p2, Result: result})
+        if !found1 {
+            return nil, errorT("fout.onbekende_speler_bord", rl.Board, rl.Name1)
+        }
+        if !found2 {
+            return nil, errorT("fout.onbekende_speler_bord", rl.Board, rl.Name2)
+        }
+        matches = append(matches, Match{Player1: p1, Player2: p2, Result: rl.Result, Board: rl.Board})
+    }
+    return matches, nil
+}
+
+// Optioneel veld "sleutel=waarde" uit input.txt invullen
+func setPlayerField(p *Player, field string) error {
+    if field == "" {
+        return nil
     }
-    return matches, scanner.Err()
+    key, value, ok := strings.Cut(field, "=")
+    n, err := strconv.ParseFloat(value, 64)
+    if !ok || err != nil {
+        return errorT("fout.spelersveld", p.Name, field)
+    }
+    switch key {
+    case "partijen":
+        p.Games = int(n)
+    case "leeftijd":
+        p.Age = int(n)
+    case "rd":
+        p.RD = n
+    case "vol":
+        p.Volatility = n
+    case "voorlopig":
+        p.Provisional = n != 0
+    default:
+        return errorT("fout.spelersveld", p.Name, field)
+    }
+    return nil
 }
 
 func savePlayerStatus(filename string, players []Player) error {
@@ -114,78 +162,177 @@ func savePlayerStatus(filename string, players []Player) error {
     }
     defer file.Close()
 
+    // CSV, zodat namen met een komma tussen aanhalingstekens komen
+    w := csv.NewWriter(file)
     for _, player := range players {
-        line := fmt.Sprintf("%s,%d,%d,%d,%d,%.2f,%d,%s\n",
-            player.Name, player.Level, player.Rating, player.Punten,
-            player.Matchscore, player.RatOppTotal, player.RoundsPlayed,
-            strings.Join(player.Opponents, ";"))
-        file.WriteString(line)
+        var roundRatings []string
+        for _, rating := range player.RoundRatings {
+            roundRatings = append(roundRatings, strconv.Itoa(rating))
+        }
+        w.Write([]string{
+            player.Name, strconv.Itoa(player.Level), strconv.Itoa(player.Rating), strconv.Itoa(player.Punten),
+            strconv.Itoa(player.Matchscore), fmt.Sprintf("%.2f", player.RatOppTotal), strconv.Itoa(player.RoundsPlayed),
+            strings.Join(player.Opponents, ";"), strconv.Itoa(player.Byes), strings.Join(roundRatings, ";"),
+        })
     }
-    return nil
+    w.Flush()
+    return w.Error()
 }
 
-func loadPlayerStatus(filename string, players []Player) error {
+// Statusbestand inlezen als losse spelers, zonder ze aan de huidige spelerslijst te koppelen
+func readStatusFile(filename string) ([]Player, error) {
     file, err := os.Open(filename)
     if err != nil {
-        return err
+        return nil, err
     }
     defer file.Close()
 
-    scanner := bufio.NewScanner(file)
-    for scanner.Scan() {
-        line := scanner.Text()
-        parts := strings.Split(line, ",")
-        if len(parts) != 8 {
+    var players []Player
+    // Oudere statusbestanden zijn zonder aanhalingstekens geschreven; die regels lezen als gewone CSV
+    r := csv.NewReader(file)
+    r.FieldsPerRecord = -1
+    r.LazyQuotes = true
+    for {
+        parts, err := r.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, err
+        }
+        // Oudere statusbestanden hebben 8 kolommen (zonder byes) of 9 (zonder ratings per ronde)
+        if len(parts) < 8 || len(parts) > 10 {
             continue
         }
-        name := parts[0]
         level, _ := strconv.Atoi(parts[1])
         rating, _ := strconv.Atoi(parts[2])
         punten, _ := strconv.Atoi(parts[3])
-        matchscore, _ := strconv.Atoi(parts[4])
+        matchscore, err := strconv.Atoi(parts[4])
+        if err != nil {
+            // Oudere versies schreven de matchscore als kommagetal ("3.00")
+            ms, _ := strconv.ParseFloat(parts[4], 64)
+            matchscore = int(ms)
+        }
         ratOppTotal, _ := strconv.ParseFloat(parts[5], 64)
         roundsPlayed, _ := strconv.Atoi(parts[6])
-        opponents := strings.Split(parts[7], ";")
+        opponents := []string{}
+        if parts[7] != "" {
+            opponents = strings.Split(parts[7], ";")
+        }
+        byes := 0
+        if len(parts) >= 9 {
+            byes, _ = strconv.Atoi(parts[8])
+        }
+        var roundRatings []int
+        if len(parts) == 10 && parts[9] != "" {
+            for _, field := range strings.Split(parts[9], ";") {
+                rating, _ := strconv.Atoi(field)
+                roundRatings = append(roundRatings, rating)
+            }
+        }
+        players = append(players, Player{
+            Name:         parts[0],
+            Level:        level,
+            Rating:       rating,
+            Punten:       punten,
+            Matchscore:   matchscore,
+            Opponents:    opponents,
+            RatOppTotal:  ratOppTotal,
+            RoundsPlayed: roundsPlayed,
+            Byes:         byes,
+            RoundRatings: roundRatings,
+        })
+    }
+    return players, nil
+}
+
+func loadPlayerStatus(filename string, players []Player) error {
+    status, err := readStatusFile(filename)
+    if err != nil {
+        return err
+    }
 
+    for _, s := range status {
         for i := range players {
-            if players[i].Name == name {
-                players[i].Level = level
-                players[i].Rating = rating
-                players[i].Punten = punten
-                players[i].Matchscore = matchscore
-                players[i].RatOppTotal = ratOppTotal
-                players[i].RoundsPlayed = roundsPlayed
-                players[i].Opponents = opponents
+            if players[i].Name == s.Name {
+                players[i].Level = s.Level
+                players[i].Rating = s.Rating
+                players[i].Punten = s.Punten
+                players[i].Matchscore = s.Matchscore
+                players[i].RatOppTotal = s.RatOppTotal
+                players[i].RoundsPlayed = s.RoundsPlayed
+                players[i].Opponents = s.Opponents
+                players[i].Byes = s.Byes
+                players[i].RoundRatings = s.RoundRatings
                 break
             }
         }
     }
-    return scanner.Err()
+    return nil
 }
 
-// Spelers sorteren op Punten, dan Matchscore, dan RatOpp, dan Rating (allemaal aflopend)
+// Spelers sorteren op Punten, dan op de tiebreaks uit de configuratie (standaard Matchscore, RatOpp, Rating; allemaal aflopend)
 func sortPlayers(players []Player) {
     sort.Slice(players, func(i, j int) bool {
         if players[i].Punten != players[j].Punten {
             return players[i].Punten > players[j].Punten
         }
-        if players[i].Matchscore != players[j].Matchscore {
-            return players[i].Matchscore > players[j].Matchscore
-        }
-        var ratOppI, ratOppJ float64
-        if players[i].RoundsPlayed > 0 {
-            ratOppI = players[i].RatOppTotal / float64(players[i].RoundsPlayed)
-        }
-        if players[j].RoundsPlayed > 0 {
-            ratOppJ = players[j].RatOppTotal / float64(players[j].RoundsPlayed)
-        }
-        if ratOppI != ratOppJ {
-            return ratOppI > ratOppJ
+        for _, tiebreak := range config.Tiebreaks {
+            a, b := tiebreakValue(players[i], tiebreak), tiebreakValue(players[j], tiebreak)
+            if a != b {
+                return a > b
+            }
         }
-        return players[i].Rating > players[j].Rating
+        return false
     })
 }
 
+// Waarde van een tiebreak voor een speler; hoger is beter
+func tiebreakValue(p Player, tiebreak string) float64 {
+    switch tiebreak {
+    case "matchscore":
+        return float64(p.Matchscore)
+    case "ratopp":
+        if p.RoundsPlayed > 0 {
+            return p.RatOppTotal / float64(p.RoundsPlayed)
+        }
+        return 0
+    case "tpr":
+        return linearTPR(p)
+    case "tpr_fide":
+        return fideTPR(p)
+    case "rating":
+        return float64(p.Rating)
+    }
+    return 0
+}
+
+// Kolomkop van een tiebreak in de actieve taal
+func tiebreakLabel(tiebreak string) string {
+    switch tiebreak {
+    case "matchscore":
+        return T("doc.matchscore")
+    case "ratopp":
+        return T("doc.ratopp")
+    case "tpr":
+        return T("doc.tpr_lineair")
+    case "tpr_fide":
+        return T("doc.tpr_fide")
+    case "rating":
+        return T("doc.rating")
+    }
+    return tiebreak
+}
+
+// Kolomkoppen van de tiebreaks uit de configuratie, in volgorde
+func tiebreakLabels() []string {
+    var labels []string
+    for _, tiebreak := range config.Tiebreaks {
+        labels = append(labels, tiebreakLabel(tiebreak))
+    }
+    return labels
+}
+
 // Check of twee spelers al tegen elkaar hebben gespeeld
 func hasPlayed(p1, p2 Player) bool {
     for _, opp := range p1.Opponents {
@@ -312,75 +459,67 @@ func pairPlayers(players []Player) []Match {
     return matches
 }
 
-// RondeX.txt genereren
+// RondeX.txt genereren, met een bordnummer voor elke pairing
 func generateRoundFile(round int, matches []Match) error {
     filename := fmt.Sprintf("ronde%d.txt", round)
-    file, err := os.Create(filename)
-    if err != nil {
-        return err
-    }
-    defer file.Close()
+    assignBoards(matches)
 
+    var lines []roundLine
     for _, match := range matches {
+        rl := roundLine{
+            Board:   match.Board,
+            Name1:   match.Player1.Name,
+            Level1:  match.Player1.Level,
+            Rating1: match.Player1.Rating,
+            Result:  "0-0",
+            Name2:   match.Player2.Name,
+            Level2:  match.Player2.Level,
+            Rating2: match.Player2.Rating,
+        }
         if match.Player2.Name == "Bye" {
-            line := fmt.Sprintf("%s LVL %d (%d rating)   1-0   Bye\n",
-                match.Player1.Name, match.Player1.Level, match.Player1.Rating)
-            file.WriteString(line)
-        } else {
-            line := fmt.Sprintf("%s LVL %d (%d rating)   0-0   %s LVL %d (%d rating)\n",
-                match.Player1.Name, match.Player1.Level, match.Player1.Rating,
-                match.Player2.Name, match.Player2.Level, match.Player2.Rating)
-            file.WriteString(line)
+            rl.Result = "1-0"
         }
+        lines = append(lines, rl)
     }
-    return nil
+    return writeRoundLines(filename, lines)
 }
 
 // Scores inlezen uit rondeX.txt
 func readRoundResults(filename string) ([]Result, error) {
-    file, err := os.Open(filename)
+    lines, err := readRoundLines(filename)
     if err != nil {
         return nil, err
     }
-    defer file.Close()
 
     var results []Result
-    scanner := bufio.NewScanner(file)
-    for scanner.Scan() {
-        line := scanner.Text()
-        parts := strings.Split(line, "   ")
-        if len(parts) != 3 {
-            continue
-        }
-        p1Name := strings.Split(parts[0], " LVL")[0]
-        p2Name := strings.Split(parts[2], " LVL")[0]
-        if p2Name == "Bye" {
-            p2Name = "Bye"
-        }
-        scores := strings.Split(parts[1], "-")
-        if len(scores) != 2 {
+    for _, rl := range lines {
+        score1, score2, ok := parseScore(rl.Result)
+        if !ok {
             continue
         }
-        score1, _ := strconv.Atoi(scores[0])
-        score2, _ := strconv.Atoi(scores[1])
         results = append(results, Result{
-            Player1: p1Name,
-            Player2: p2Name,
+            Player1: rl.Name1,
+            Player2: rl.Name2,
             Score1:  score1,
             Score2:  score2,
+            Board:   rl.Board,
         })
     }
-    return results, scanner.Err()
+    return results, nil
 }
 
 // Spelers updaten met Punten, Matchscore en RatOpp
 func updatePlayers(players []Player, results []Result) {
+    for i := range players {
+        players[i].RoundRatings = append(players[i].RoundRatings, players[i].Rating)
+    }
     for _, result := range results {
         for i := range players {
             if players[i].Name == result.Player1 {
                 if result.Player2 == "Bye" {
                     players[i].Punten += 2      // 2 punten voor een "Bye" (overwinning)
                     players[i].Matchscore += 1 // Matchscore +1 (1-0 overwinning)
+                    players[i].Byes++
                     // Geen opponent toevoegen
                     // RoundsPlayed niet verhogen
                 } else {
@@ -434,7 +573,7 @@ func updateMatchResults(matches []Match, results []Result) {
 }
 
 func sortMatches(matches []Match) {
-    sort.Slice(matches, func(i, j int) bool {
+    sort.SliceStable(matches, func(i, j int) bool {
         // Controleer of een match een "Bye" bevat
         isByeI := matches[i].Player2.Name == "Bye"
         isByeJ := matches[j].Player2.Name == "Bye"
@@ -456,96 +595,70 @@ func sortMatches(matches []Match) {
     })
 }
 
-// HTML genereren met CSS voor centrering, randen en padding
-func generateHTML(round int, players []Player, matches []Match) error {
-    // Sorteer de matches van beste naar slechtste spelers
+// Bordnummers toekennen. Spelers met een vast bord (board_pins in config.json) krijgen dat bord; als twee
+// partijen hetzelfde bord willen, krijgt de eerste in de volgorde van sortMatches het. De andere partijen
+// vullen de vrije borden in die volgorde en byes komen achter het hoogste bord.
+func assignBoards(matches []Match) {
     sortMatches(matches)
-    const tmpl = `
-    <html>
-    <head>
-    <title>Ronde {{.Round}}</title>
-    <style>
-    body {
-        text-align: center;
-    }
-    table {
-        border-collapse: collapse;
-        margin: auto;
-    }
-    table, th, td {
-        border: 1px solid lightgray;
-        text-align: center;
-        padding: 5px;
-    }
-    </style>
-    </head>
-    <body>
-    <h1>Ronde {{.Round}}</h1>
-    <h2>Standings</h2>
-    <table>
-        <tr>
-            <th>Nr.</th>
-            <th>Naam</th>
-            <th>Level</th>
-            <th>Rating</th>
-            <th>Punten</th>
-            <th>Matchscore</th>
-            <th>RatOpp</th>
-        </tr>
-        {{range $index, $player := .Players}}
-        <tr>
-            <td>{{add $index 1}}</td>
-            <td>{{$player.Name}}</td>
-            <td>{{$player.Level}}</td>
-            <td>{{$player.Rating}}</td>
-            <td>{{$player.Punten}}</td>
-            <td>{{$player.Matchscore}}</td>
-            <td>{{if $player.RoundsPlayed}}{{printf "%.2f" (div $player.RatOppTotal $player.RoundsPlayed)}}{{else}}0{{end}}</td>
-        </tr>
-        {{end}}
-    </table>
-    <h2>Pairings</h2>
-    <table>
-        <tr>
-            <th>Nr.</th>
-            <th>Naam</th>
-            <th>Level</th>
-            <th>Rating</th>
-            <th>Score</th>
-            <th>Naam</th>
-            <th>Level</th>
-            <th>Rating</th>
-        </tr>
-        {{range $index, $match := .Matches}}
-        <tr>
-            <td>{{add $index 1}}</td>
-            <td>{{$match.Player1.Name}}</td>
-            <td>{{$match.Player1.Level}}</td>
-            <td>{{$match.Player1.Rating}}</td>
-            <td>{{$match.Result}}</td>
-            <td>{{$match.Player2.Name}}</td>
-            {{if eq $match.Player2.Name "Bye"}}
-            <td>-</td>
-            <td>-</td>
-            {{else}}
-            <td>{{$match.Player2.Level}}</td>
-            <td>{{$match.Player2.Rating}}</td>
-            {{end}}
-        </tr>
-        {{end}}
-    </table>
-    </body>
-    </html>`
-
-    t := template.Must(template.New("round").Funcs(template.FuncMap{
-        "add": func(a int, b int) int { return a + b },
-        "div": func(a float64, b int) float64 {
-            if b == 0 {
-                return 0 // Voorkomt deling door nul
-            }
-            return a / float64(b)
-        },
-    }).Parse(tmpl))
+    taken := make(map[int]bool)
+    for i := range matches {
+        matches[i].Board = 0
+        if matches[i].Player2.Name == "Bye" {
+            continue
+        }
+        board := pinnedBoard(matches[i])
+        if board > 0 && !taken[board] {
+            matches[i].Board = board
+            taken[board] = true
+        }
+    }
+
+    next, highest := 1, 0
+    for i := range matches {
+        if matches[i].Player2.Name != "Bye" && matches[i].Board == 0 {
+            for taken[next] {
+                next++
+            }
+            matches[i].Board = next
+            taken[next] = true
+        }
+        if matches[i].Board > highest {
+            highest = matches[i].Board
+        }
+    }
+    for i := range matches {
+        if matches[i].Player2.Name == "Bye" {
+            highest++
+            matches[i].Board = highest
+        }
+    }
+    sortByBoard(matches)
+}
+
+// Vast bord van een partij; bij twee spelers met een vast bord het laagste
+func pinnedBoard(match Match) int {
+    board := config.BoardPins[match.Player1.Name]
+    if pin := config.BoardPins[match.Player2.Name]; pin > 0 && (board == 0 || pin < board) {
+        board = pin
+    }
+    return board
+}
+
+// Matches in bordvolgorde zetten
+func sortByBoard(matches []Match) {
+    sort.SliceStable(matches, func(i, j int) bool {
+        return matches[i].Board < matches[j].Board
+    })
+}
+
+// HTML genereren met CSS voor centrering, randen en padding
+func generateHTML(round int, players []Player, matches []Match, allResults [][]Result) error {
+    // Zelfde bordvolgorde als rondeX.txt
+    sortByBoard(matches)
+    t, err := loadTemplate(nil, "ronde.html")
+    if err != nil {
+        return err
+    }
 
     filename := fmt.Sprintf("ronde%d.html", round)
     file, err := os.Create(filename)
@@ -558,34 +671,57 @@ func generateHTML(round int, players []Player, matches []Match) error {
     data := struct {
         Round   int
         Players []Player
-        Matches []Match
-    }{Round: round, Players: players, Matches: matches}
+        Matches []StakedMatch
+        System  string
+        Charts  []template.HTML
+    }{Round: round, Players: players, Matches: stakeMatches(matches, stakeState(players, allResults, round)), System: activeRatingSystem().Name(),
+        Charts: progressionCharts(replayRounds(players, allResults))}
     return t.Execute(file, data)
 }
 
 // RATING BEREKENING SPELERS
-func getBonus(theRange int, maxRatingAdd int, ratingOpponent int, ownRating int, result string) int {
-    var bonus int
-    perRating := (2 * theRange) / maxRatingAdd
-    low := ownRating - theRange
-    if ratingOpponent <= ownRating-theRange {
+// Ratingwijziging volgens de bonustabel. Met rounding "truncate" wordt na elke deling naar nul afgekapt
+// (de oorspronkelijke berekening met gehele getallen), met "round" wordt pas het eindresultaat afgerond.
+func getBonus(cfg BonusConfig, ratingOpponent int, ownRating int, result string) int {
+    cut := math.Trunc
+    if cfg.Rounding == "round" {
+        cut = func(x float64) float64 { return x }
+    }
+    maxRatingAdd := float64(cfg.MaxGain)
+    loss := func(x float64) float64 { return cut(x * float64(cfg.LossPercent) / 100) }
+
+    var bonus float64
+    perRating := cut(float64(2*cfg.Range) / maxRatingAdd)
+    low := ownRating - cfg.Range
+    if ratingOpponent <= ownRating-cfg.Range {
         bonus = 0
-    } else if ratingOpponent >= ownRating+theRange {
+    } else if ratingOpponent >= ownRating+cfg.Range {
         bonus = maxRatingAdd
     } else {
-        bonus = (ratingOpponent - low) / perRating
+        bonus = math.Min(maxRatingAdd, cut(float64(ratingOpponent-low)/perRating))
     }
+
+    var change float64
     if result == "w" {
-        return bonus
+        change = bonus
     } else if result == "d" {
-        if ratingOpponent >= ownRating {
-            return bonus / 2
-        } else {
-            return 0 - ((((maxRatingAdd - bonus) / 2) * 75) / 100)
+        switch cfg.DrawRule {
+        case "zero":
+            change = 0
+        case "symmetric":
+            // Gemiddelde van winst en verlies
+            change = cut((bonus - loss(maxRatingAdd-bonus)) / 2)
+        default: // "stronger_only": alleen winst tegen een even sterke of sterkere tegenstander
+            if ratingOpponent >= ownRating {
+                change = cut(bonus / 2)
+            } else {
+                change = 0 - loss(cut((maxRatingAdd-bonus)/2))
+            }
         }
     } else {
-        return 0 - (((maxRatingAdd - bonus) * 75) / 100)
+        change = 0 - loss(maxRatingAdd-bonus)
     }
+    return int(math.Round(change))
 }
 
 func getMatchOutcome(playerName string, result Result) string {
@@ -612,11 +748,11 @@ func getMatchOutcome(playerName string, result Result) string {
 func outcomeToString(outcome string) string {
     switch outcome {
     case "w":
-        return "WIN"
+        return T("doc.uitslag_winst")
     case "d":
-        return "DRAW"
+        return T("doc.uitslag_remise")
     case "l":
-        return "LOSE"
+        return T("doc.uitslag_verlies")
     default:
         return ""
     }
@@ -624,6 +760,7 @@ func outcomeToString(outcome string) string {
 
 // Structs voor HTML template
 type PlayerResult struct {
+    Round          int
     Rank           int
     OpponentName   string
     OpponentLevel  int
@@ -631,145 +768,169 @@ type PlayerResult struct {
     MatchResult    string
     Outcome        string
     Bonus          int
+    Expected       float64 // Verwachte score volgens het ratingsysteem
+    Actual         float64 // Behaalde score: 1, 0.5 of 0 (byes 0)
+    Factor         float64 // Weging op het scoreverschil (0 = niet gebruikt)
+    PuntenNa       int     // Punten na deze ronde
+    RankNa         int     // Plaats in de stand na deze ronde (vanaf 1)
 }
 
 type PlayerData struct {
     Name          string
     Level         int
+    Rank          int // Plaats in de eindstand (vanaf 1)
+    Punten        int
+    Matchscore    int
     InitialRating int
     Results       []PlayerResult
     TotalAdd      int
     NewRating     int
+    Games         int     // Partijen na dit toernooi: partijen= uit input.txt plus de gespeelde partijen (zonder byes)
+    Age           int
+    RD            float64 // Glicko-2: deviatie bij de start en na het toernooi (0 bij andere systemen)
+    NewRD         float64
+    Volatility    float64
+    NewVolatility float64
+    Provisional   bool    // Voorlopige speler bij de start
+    Estimated     bool    // Nieuwe rating geschat uit de performance
+    IntervalLow   int     // 95%-betrouwbaarheidsinterval van de nieuwe rating (NewRating ± 2 RD)
+    IntervalHigh  int
+    TPR           float64 // Performance rating, lineaire benadering
+    TPRFide       float64 // Performance rating, FIDE dp-tabel
 }
 
-func generateRatingHTML(players []Player, allResults [][]Result, initialRatings map[string]int) error {
-    var theRange int = 675
-    var maxRatingAdd int = 40
-
+// Ratingwijzigingen per speler berekenen uit de resultaten van alle rondes, met het gekozen ratingsysteem
+func buildRatingData(players []Player, allResults [][]Result, initialRatings map[string]int, system RatingSystem) []PlayerData {
     // Sorteer spelers voor ranking
     sortPlayers(players)
     playerRank := make(map[string]int)
+    byName := make(map[string]Player)
     for i, p := range players {
         playerRank[p.Name] = i
+        byName[p.Name] = p
+    }
+
+    // Stand na elke ronde voor het verloop van punten en plaats
+    standings := replayRounds(resetPlayers(players), allResults)
+    replayed := make(map[string]Player)
+    if len(standings) > 0 {
+        for _, p := range standings[len(standings)-1] {
+            replayed[p.Name] = p
+        }
+    }
+    roundRank := make([]map[string]int, len(standings))
+    roundPunten := make([]map[string]int, len(standings))
+    for r, standing := range standings {
+        roundRank[r] = make(map[string]int)
+        roundPunten[r] = make(map[string]int)
+        for i, p := range standing {
+            roundRank[r][p.Name] = i + 1
+            roundPunten[r][p.Name] = p.Punten
+        }
+    }
+
+    // Ratingwijzigingen per periode: alles in één keer of per ronde, zie ratingPeriods
+    type ratedGame struct {
+        OpponentRating int
+        Change         GameChange
+    }
+    state := ratingState(players, initialRatings)
+    rated := make(map[string]map[int]ratedGame) // Per speler de partij van elke ronde
+    first := make(map[string]RatingChange)      // Eerste en laatste periode, voor deviatie en volatiliteit
+    last := make(map[string]RatingChange)
+    totals := make(map[string]int)
+    for i, period := range ratingPeriods(len(allResults)) {
+        changes, games := ratePeriod(state, allResults, period, system)
+        for name, change := range changes {
+            if i == 0 {
+                first[name] = change
+            }
+            last[name] = change
+            totals[name] += change.Total
+            if rated[name] == nil {
+                rated[name] = make(map[int]ratedGame)
+            }
+            for j, game := range change.Games {
+                pg := games[name][j]
+                rated[name][pg.Round] = ratedGame{OpponentRating: pg.Game.OpponentRating, Change: game}
+            }
+        }
     }
 
     // Maak data voor template
     var playerData []PlayerData
     for _, player := range players {
-        totalAdd := 0
         var results []PlayerResult
-        for _, roundResults := range allResults {
+        for r, roundResults := range allResults {
             for _, result := range roundResults {
-                if result.Player1 == player.Name || result.Player2 == player.Name {
-                    var opponentName string
-                    var opponentRating, opponentLevel int
-                    var outcome, matchResult string
-                    if result.Player1 == player.Name {
-                        opponentName = result.Player2
-                        outcome = getMatchOutcome(player.Name, result)
-                        for _, p := range players {
-                            if p.Name == opponentName {
-                                opponentRating = initialRatings[p.Name]
-                                opponentLevel = p.Level
-                                break
-                            }
-                        }
-                        matchResult = fmt.Sprintf("%d-%d", result.Score1, result.Score2)
-                    } else {
-                        opponentName = result.Player1
-                        outcome = getMatchOutcome(player.Name, result)
-                        for _, p := range players {
-                            if p.Name == opponentName {
-                                opponentRating = initialRatings[p.Name]
-                                opponentLevel = p.Level
-                                break
-                            }
-                        }
-                        matchResult = fmt.Sprintf("%d-%d", result.Score2, result.Score1)
-                    }
-                    if opponentName != "Bye" {
-                        bonus := getBonus(theRange, maxRatingAdd, opponentRating, initialRatings[player.Name], outcome)
-                        totalAdd += bonus
-                        results = append(results, PlayerResult{
-                            Rank:           playerRank[opponentName], // Rank van de tegenstander
-                            OpponentName:   opponentName,
-                            OpponentLevel:  opponentLevel,
-                            OpponentRating: opponentRating,
-                            MatchResult:    matchResult,
-                            Outcome:        outcomeToString(outcome),
-                            Bonus:          bonus,
-                        })
-                    }
+                if result.Player1 != player.Name && result.Player2 != player.Name {
+                    continue
+                }
+                opponentName, own, other := result.Player2, result.Score1, result.Score2
+                if result.Player2 == player.Name {
+                    opponentName, own, other = result.Player1, result.Score2, result.Score1
+                }
+                pr := PlayerResult{
+                    Round:          r + 1,
+                    Rank:           playerRank[opponentName], // Rank van de tegenstander
+                    OpponentName:   opponentName,
+                    OpponentLevel:  byName[opponentName].Level,
+                    OpponentRating: initialRatings[opponentName],
+                    MatchResult:    fmt.Sprintf("%d-%d", own, other),
+                    Outcome:        outcomeToString(getMatchOutcome(player.Name, result)),
+                    PuntenNa:       roundPunten[r][player.Name],
+                    RankNa:         roundRank[r][player.Name],
+                }
+                // Een bye telt niet mee voor de rating, maar komt wel in de rondegeschiedenis
+                if game, ok := rated[player.Name][r]; ok && opponentName != "Bye" {
+                    pr.OpponentRating = game.OpponentRating // Bij "per_round" de rating in die ronde
+                    pr.Bonus = int(math.Round(game.Change.Change))
+                    pr.Expected = game.Change.Expected
+                    pr.Actual = game.Change.Actual
+                    pr.Factor = game.Change.Factor
                 }
+                results = append(results, pr)
             }
         }
+
+        newRating := state[player.Name].Rating
+        change := RatingChange{RD: first[player.Name].RD, Volatility: first[player.Name].Volatility,
+            NewRD: last[player.Name].NewRD, NewVolatility: last[player.Name].NewVolatility}
         playerData = append(playerData, PlayerData{
             Name:          player.Name,
             Level:         player.Level,
+            Rank:          playerRank[player.Name] + 1,
+            Punten:        player.Punten,
+            Matchscore:    player.Matchscore,
             InitialRating: initialRatings[player.Name],
             Results:       results,
-            TotalAdd:      totalAdd,
-            NewRating:     initialRatings[player.Name] + totalAdd,
+            TotalAdd:      totals[player.Name],
+            NewRating:     newRating,
+            Games:         state[player.Name].Games,
+            Age:           player.Age,
+            RD:            change.RD,
+            NewRD:         change.NewRD,
+            Volatility:    change.Volatility,
+            NewVolatility: change.NewVolatility,
+            Provisional:   player.Provisional,
+            Estimated:     last[player.Name].Estimated,
+            IntervalLow:   newRating - int(math.Round(2*change.NewRD)),
+            IntervalHigh:  newRating + int(math.Round(2*change.NewRD)),
+            TPR:           linearTPR(replayed[player.Name]),
+            TPRFide:       fideTPR(replayed[player.Name]),
         })
     }
+    return playerData
+}
+
+func generateRatingHTML(players []Player, allResults [][]Result, initialRatings map[string]int) error {
+    system := activeRatingSystem()
+    playerData := buildRatingData(players, allResults, initialRatings, system)
 
-    // HTML template
-    const tmpl = `
-    <html>
-    <head>
-    <style>
-    body {
-        text-align: center;
-    }
-    table {
-        border-collapse: collapse;
-        margin: auto;
-    }
-    th, td {
-        border: 1px solid lightgray;
-        padding: 10px;
-        text-align: center;
-    }
-    </style>
-    </head>
-    <body>
-    {{range .Players}}
-    <p>{{.Name}} - Level {{.Level}}</p>
-    <p>EIGEN RATING START: {{.InitialRating}}</p>
-    <table>
-        <tr>
-            <th>Rank</th>
-            <th>Naam</th>
-            <th>Level</th>
-            <th>Rating</th>
-            <th>Match Result</th>
-            <th>Resultaat</th>
-            <th>Rating erbij</th>
-        </tr>
-        {{range .Results}}
-        {{if ne .OpponentName "Bye"}}
-        <tr>
-            <td>{{add .Rank 1}}</td>
-            <td>{{.OpponentName}}</td>
-            <td>{{.OpponentLevel}}</td>
-            <td>{{.OpponentRating}}</td>
-            <td>{{.MatchResult}}</td>
-            <td>{{.Outcome}}</td>
-            <td>{{.Bonus}}</td>
-        </tr>
-        {{end}}
-        {{end}}
-    </table>
-    <p>RATING ERBIJ: {{.TotalAdd}}</p>
-    <p>NIEUWE RATING: {{.NewRating}}</p>
-    <hr>
-    {{end}}
-    </body>
-    </html>`
-
-    t := template.Must(template.New("rating").Funcs(template.FuncMap{
-        "add": func(a int, b int) int { return a + b },
-    }).Parse(tmpl))
+    t, err := loadTemplate(nil, "overview.html")
+    if err != nil {
+        return err
+    }
 
     file, err := os.Create("overview.html")
     if err != nil {
@@ -778,52 +939,155 @@ func generateRatingHTML(players []Player, allResults [][]Result, initialRatings
     defer file.Close()
 
     data := struct {
+        System  string
+        Margin  bool // Kolom met de weging op het scoreverschil tonen
         Players []PlayerData
-    }{Players: playerData}
+        Charts  []template.HTML
+    }{
+        System:  system.Name(),
+        Margin:  config.Rating.Margin.Curve != "none",
+        Players: playerData,
+        Charts:  progressionCharts(replayRounds(players, allResults)),
+    }
     return t.Execute(file, data)
 }
 
+// Rating van een speler bij de start van het toernooi; bij "per_round" is Rating de huidige rating
+func startRating(p Player) int {
+    if len(p.RoundRatings) > 0 {
+        return p.RoundRatings[0]
+    }
+    return p.Rating
+}
+
+// Ratings bij de start van het toernooi per speler
+func startRatings(players []Player) map[string]int {
+    ratings := make(map[string]int)
+    for _, p := range players {
+        ratings[p.Name] = startRating(p)
+    }
+    return ratings
+}
+
+// Resultaten van ronde 1 t/m de huidige ronde inlezen; ontbrekende rondes worden gemeld en overgeslagen
+func readAllResults(currentRound int) [][]Result {
+    var allResults [][]Result
+    for r := 1; r <= currentRound; r++ {
+        filename := fmt.Sprintf("ronde%d.txt", r)
+        results, err := readRoundResults(filename)
+        if err != nil {
+            fmt.Println(T("fout.inlezen_ronde", r), err)
+            continue
+        }
+        if r == currentRound && !roundPlayed(results) {
+            continue // Scores van de huidige ronde zijn nog niet ingevuld
+        }
+        allResults = append(allResults, results)
+    }
+    return allResults
+}
+
+// Een ronde is gespeeld zodra minstens één partij (geen bye) een andere score dan 0-0 heeft
+func roundPlayed(results []Result) bool {
+    for _, result := range results {
+        if result.Player2 != "Bye" && (result.Score1 != 0 || result.Score2 != 0) {
+            return true
+        }
+    }
+    return false
+}
+
+// Een volledige regel van stdin lezen (ook met spaties), zonder buffering zodat fmt.Scanln blijft werken
+func leesRegel() string {
+    var sb strings.Builder
+    buf := make([]byte, 1)
+    for {
+        n, err := os.Stdin.Read(buf)
+        if n == 0 || err != nil || buf[0] == '\n' {
+            break
+        }
+        sb.WriteByte(buf[0])
+    }
+    return strings.TrimSpace(sb.String())
+}
+
 // Hoofdprogramma met menu
 func main() {
+    lang := flag.String("lang", defaultLanguage, "taal van menu en uitvoer ("+strings.Join(availableLanguages(), ", ")+")")
+    flag.Parse()
+    if err := setLanguage(*lang); err != nil {
+        fmt.Println(err)
+        return
+    }
+
+    var err error
+    config, err = loadConfig("config.json")
+    if err != nil {
+        fmt.Println(T("fout.config"), err)
+        return
+    }
+
     players, err := readPlayers("input.txt")
     if err != nil {
-        fmt.Println("Fout bij inlezen spelers:", err)
+        fmt.Println(T("fout.spelers"), err)
         return
     }
+    // Ratings bij de start uit de ratingdatabase
+    if config.Database.File != "" {
+        db, err := loadRatingDB(config.Database.File)
+        if err != nil {
+            fmt.Println(T("fout.db"), err)
+            return
+        }
+        if n := applyRatingDB(db, players, tournamentID()); n > 0 {
+            fmt.Println(T("msg.db_geladen", n, config.Database.File))
+        }
+    }
 
     currentRound := 0
     var lastMatches []Match
 
     for {
-        fmt.Println("\nMenu:")
-        fmt.Println("0. Verander huidige rondenr")
-        fmt.Println("1. Genereer nieuwe ronde")
-        fmt.Println("2. Genereer finale ronde")
-        fmt.Println("3. Verwerk scores van huidige ronde")
-        fmt.Println("4. Genereer HTML")
-        fmt.Println("5. Genereer overview + new_ratings")
-        fmt.Println("6. Exit")
-        fmt.Print("Kies een optie: ")
+        fmt.Println(T("menu.titel"))
+        fmt.Println(T("menu.0"))
+        fmt.Println(T("menu.1"))
+        fmt.Println(T("menu.2"))
+        fmt.Println(T("menu.3"))
+        fmt.Println(T("menu.4"))
+        fmt.Println(T("menu.5"))
+        fmt.Println(T("menu.6"))
+        fmt.Println(T("menu.7"))
+        fmt.Println(T("menu.8"))
+        fmt.Println(T("menu.9"))
+        fmt.Println(T("menu.10"))
+        fmt.Println(T("menu.11"))
+        fmt.Println(T("menu.12"))
+        fmt.Println(T("menu.13"))
+        fmt.Println(T("menu.14"))
+        fmt.Println(T("menu.15"))
+        fmt.Println(T("menu.16"))
+        fmt.Println(T("menu.17"))
+        fmt.Print(T("menu.kies"))
 
         var choice string
         fmt.Scanln(&choice)
 
         switch choice {
         case "0":
-            fmt.Print("Voer nieuwe rondenr in: ")
+            fmt.Print(T("vraag.rondenr"))
             var newRound string
             fmt.Scanln(&newRound)
             if roundNum, err := strconv.Atoi(newRound); err == nil {
                 currentRound = roundNum
-                fmt.Println("Huidige rondenr is nu:", currentRound)
+                fmt.Println(T("msg.rondenr"), currentRound)
 
                 // Probeer spelerstatus van de huidige ronde te laden
                 statusFile := fmt.Sprintf("ronde%d_status.txt", currentRound)
                 if _, err := os.Stat(statusFile); err == nil {
                     if err := loadPlayerStatus(statusFile, players); err != nil {
-                        fmt.Println("Fout bij laden spelerstatus:", err)
+                        fmt.Println(T("fout.laden_status"), err)
                     } else {
-                        fmt.Println("Spelerstatus geladen voor ronde", currentRound)
+                        fmt.Println(T("msg.status_geladen"), currentRound)
                     }
                 } else {
                     // Zoek naar de meest recente eerdere status
@@ -832,16 +1096,16 @@ func main() {
                         prevStatusFile := fmt.Sprintf("ronde%d_status.txt", r)
                         if _, err := os.Stat(prevStatusFile); err == nil {
                             if err := loadPlayerStatus(prevStatusFile, players); err != nil {
-                                fmt.Println("Fout bij laden spelerstatus van ronde", r, ":", err)
+                                fmt.Println(T("fout.laden_status_ronde", r), err)
                             } else {
-                                fmt.Println("Spelerstatus geladen van ronde", r)
+                                fmt.Println(T("msg.status_geladen_van"), r)
                                 loaded = true
                                 break
                             }
                         }
                     }
                     if !loaded {
-                        fmt.Println("Geen eerdere spelerstatus gevonden - start met schone lei")
+                        fmt.Println(T("msg.geen_status"))
                     }
                 }
 
@@ -850,97 +1114,263 @@ func main() {
                 if _, err := os.Stat(filename); err == nil {
                     lastMatches, err = loadMatches(filename, players)
                     if err != nil {
-                        fmt.Println("Fout bij laden matches:", err)
+                        fmt.Println(T("fout.laden_matches"), err)
                     } else {
-                        fmt.Println("Matches geladen voor ronde", currentRound)
+                        fmt.Println(T("msg.matches_geladen"), currentRound)
                     }
                 } else {
-                    fmt.Println("Geen matches gevonden voor ronde", currentRound)
+                    fmt.Println(T("msg.geen_matches"), currentRound)
                     lastMatches = nil // Reset matches als er geen bestand is
                 }
             } else {
-                fmt.Println("Ongeldig rondenr")
+                fmt.Println(T("msg.ongeldig_rondenr"))
             }
 
         case "1":
             currentRound++
             lastMatches = pairPlayers(players)
             if err := generateRoundFile(currentRound, lastMatches); err != nil {
-                fmt.Println("Fout bij genereren ronde:", err)
+                fmt.Println(T("fout.genereren_ronde"), err)
             } else {
-                fmt.Printf("Ronde %d gegenereerd. Vul de scores in in ronde%d.txt\n", currentRound, currentRound)
+                fmt.Println(T("msg.ronde_gegenereerd", currentRound, currentRound, currentRound))
+                if err := generateStakesText(currentRound, players, lastMatches, readAllResults(currentRound-1)); err != nil {
+                    fmt.Println(T("fout.inzet"), err)
+                } else {
+                    fmt.Println(T("msg.inzet_gegenereerd", currentRound))
+                }
+                if err := generateLive("live", currentRound, players, lastMatches); err != nil {
+                    fmt.Println(T("fout.live"), err)
+                } else {
+                    fmt.Println(T("msg.live_gegenereerd"))
+                }
             }
 
         case "2":
             sortPlayers(players)
             if len(players) < 2 {
-                fmt.Println("Niet genoeg spelers voor finale")
+                fmt.Println(T("msg.te_weinig_finale"))
                 continue
             }
             currentRound++
             finalMatch := Match{Player1: players[0], Player2: players[1], Result: "0-0"}
             lastMatches = []Match{finalMatch}
             if err := generateRoundFile(currentRound, lastMatches); err != nil {
-                fmt.Println("Fout bij genereren finale ronde:", err)
+                fmt.Println(T("fout.genereren_finale"), err)
             } else {
-                fmt.Println("Finale ronde gegenereerd.")
+                fmt.Println(T("msg.finale_gegenereerd"))
+                if err := generateStakesText(currentRound, players, lastMatches, readAllResults(currentRound-1)); err != nil {
+                    fmt.Println(T("fout.inzet"), err)
+                } else {
+                    fmt.Println(T("msg.inzet_gegenereerd", currentRound))
+                }
+                if err := generateLive("live", currentRound, players, lastMatches); err != nil {
+                    fmt.Println(T("fout.live"), err)
+                } else {
+                    fmt.Println(T("msg.live_gegenereerd"))
+                }
             }
 
         case "3":
             filename := fmt.Sprintf("ronde%d.txt", currentRound)
+            // Uitslagen per bord uit rondeX_uitslagen.txt eerst samenvoegen met de pairings
+            resultsFile := fmt.Sprintf("ronde%d_uitslagen.txt", currentRound)
+            if _, err := os.Stat(resultsFile); err == nil {
+                if err := mergeBoardResults(filename, resultsFile); err != nil {
+                    fmt.Println(T("fout.samenvoegen"), err)
+                    continue
+                }
+                fmt.Println(T("msg.uitslagen_samengevoegd", resultsFile, filename))
+            }
             results, err := readRoundResults(filename)
             if err != nil {
-                fmt.Println("Fout bij inlezen scores:", err)
+                fmt.Println(T("fout.inlezen_scores"), err)
             } else {
                 updatePlayers(players, results) // Werk spelerstatistieken bij
+                if config.Rating.Mode == "per_round" {
+                    // Ratings na deze ronde gelden voor de pairing en RatOpp van de volgende rondes
+                    applyRoundRatings(players, readAllResults(currentRound))
+                }
                 updateMatchResults(lastMatches, results) // Werk matches bij
                 statusFile := fmt.Sprintf("ronde%d_status.txt", currentRound)
                 if err := savePlayerStatus(statusFile, players); err != nil {
-                    fmt.Println("Fout bij opslaan spelerstatus:", err)
+                    fmt.Println(T("fout.opslaan_status"), err)
+                } else {
+                    fmt.Println(T("msg.status_opgeslagen"), currentRound)
+                }
+                fmt.Println(T("msg.scores_verwerkt"), currentRound)
+                // Projectorscherm meteen bijwerken met de nieuwe stand
+                if err := generateLive("live", currentRound, players, lastMatches); err != nil {
+                    fmt.Println(T("fout.live"), err)
                 } else {
-                    fmt.Println("Spelerstatus opgeslagen voor ronde", currentRound)
+                    fmt.Println(T("msg.live_gegenereerd"))
                 }
-                fmt.Println("Scores verwerkt voor ronde", currentRound)
             }
 
         case "4":
+            allResults := readAllResults(currentRound)
             if len(lastMatches) == 0 {
-                fmt.Println("Geen matches beschikbaar om HTML te genereren. Genereer eerst een ronde of laad de matches.")
-            } else if err := generateHTML(currentRound, players, lastMatches); err != nil {
-                fmt.Println("Fout bij genereren HTML:", err)
+                fmt.Println(T("msg.geen_matches_html"))
+            } else if err := generateHTML(currentRound, players, lastMatches, allResults); err != nil {
+                fmt.Println(T("fout.html"), err)
+            } else if err := generatePrintHTML(currentRound, lastMatches); err != nil {
+                fmt.Println(T("fout.print"), err)
+            } else if err := generateStakesText(currentRound, players, lastMatches, allResults); err != nil {
+                fmt.Println(T("fout.inzet"), err)
             } else {
-                fmt.Println("HTML gegenereerd voor ronde", currentRound)
+                fmt.Println(T("msg.html_gegenereerd"), currentRound)
+                fmt.Println(T("msg.print_gegenereerd", currentRound))
+                fmt.Println(T("msg.inzet_gegenereerd", currentRound))
             }
 
         case "5":
-            // Verzamel alle resultaten van alle rondes
-            var allResults [][]Result
-            for r := 1; r <= currentRound; r++ {
-                filename := fmt.Sprintf("ronde%d.txt", r)
-                results, err := readRoundResults(filename)
-                if err != nil {
-                    fmt.Println("Fout bij inlezen results voor ronde", r, ":", err)
-                    continue
-                }
-                allResults = append(allResults, results)
-            }
-            // Verzamel initiële ratings
-            initialRatings := make(map[string]int)
-            for _, p := range players {
-                initialRatings[p.Name] = p.Rating
-            }
+            allResults := readAllResults(currentRound)
+            initialRatings := startRatings(players)
             if err := generateRatingHTML(players, allResults, initialRatings); err != nil {
-                fmt.Println("Fout bij genereren rating HTML:", err)
+                fmt.Println(T("fout.overview"), err)
+            } else {
+                fmt.Println(T("msg.overview_gegenereerd"))
+            }
+            playerData := buildRatingData(copyPlayers(players), allResults, initialRatings, activeRatingSystem())
+            if err := generateRatingUpdate(playerData); err != nil {
+                fmt.Println(T("fout.nieuwe_ratings"), err)
             } else {
-                fmt.Println("Rating update HTML gegenereerd in 'overview.html'")
+                fmt.Println(T("msg.nieuwe_ratings"))
             }
 
         case "6":
-            fmt.Println("Exit")
+            fmt.Println(T("menu.exit"))
             os.Exit(0)
 
+        case "7":
+            fmt.Print(T("vraag.importmap"))
+            dir := leesRegel()
+            if dir == "" {
+                dir = "."
+            }
+            t, problems, err := importLegacy(dir)
+            if err != nil {
+                fmt.Println(T("fout.importeren"), err)
+                continue
+            }
+            fmt.Println(T("msg.import_ingelezen", len(t.Rondes), len(t.Spelers)))
+            if len(problems) == 0 {
+                fmt.Println(T("msg.geen_inconsistenties"))
+            } else {
+                fmt.Println(T("msg.inconsistenties"))
+                for _, problem := range problems {
+                    fmt.Println(" -", problem)
+                }
+            }
+            output := filepath.Join(dir, "toernooi.json")
+            if err := saveToernooi(output, t); err != nil {
+                fmt.Println(T("fout.opslaan_toernooi"), err)
+            } else {
+                fmt.Println(T("msg.toernooi_opgeslagen"), output)
+            }
+
+        case "8":
+            fmt.Print(T("vraag.sitemap"))
+            dir := leesRegel()
+            if dir == "" {
+                dir = "site"
+            }
+            allResults := readAllResults(currentRound)
+            if err := publishSite(dir, players, allResults); err != nil {
+                fmt.Println(T("fout.site"), err)
+            } else {
+                fmt.Println(T("msg.site_gegenereerd"), dir)
+            }
+
+        case "9":
+            allResults := readAllResults(currentRound)
+            rows := buildCrosstable(resetPlayers(players), allResults)
+            if err := generateCrosstable(rows, len(allResults)); err != nil {
+                fmt.Println(T("fout.crosstable"), err)
+            } else {
+                fmt.Println(T("msg.crosstable_gegenereerd"))
+            }
+
+        case "10":
+            allResults := readAllResults(currentRound)
+            if err := generatePlayerPages("spelers", players, allResults, startRatings(players)); err != nil {
+                fmt.Println(T("fout.spelerpaginas"), err)
+            } else {
+                fmt.Println(T("msg.spelers_gegenereerd"))
+            }
+
+        case "11":
+            stats := buildStats(players, readAllResults(currentRound))
+            if err := generateStats(stats); err != nil {
+                fmt.Println(T("fout.stats"), err)
+            } else {
+                fmt.Println(T("msg.stats_gegenereerd"))
+            }
+
+        case "12":
+            // Eindstand zoals sortPlayers die geeft, herberekend uit alle rondes
+            var final []Player
+            if standings := replayRounds(players, readAllResults(currentRound)); len(standings) > 0 {
+                final = standings[len(standings)-1]
+            }
+            if err := generatePrizes(buildPrizes(final, startRatings(players), config.Prizes)); err != nil {
+                fmt.Println(T("fout.prijzen"), err)
+            } else {
+                fmt.Println(T("msg.prijzen_gegenereerd"))
+            }
+
+        case "13":
+            if err := generateLive("live", currentRound, players, lastMatches); err != nil {
+                fmt.Println(T("fout.live"), err)
+            } else {
+                fmt.Println(T("msg.live_gegenereerd"))
+            }
+
+        case "14":
+            fmt.Print(T("vraag.eigen_rating"))
+            ownRating, err := strconv.Atoi(leesRegel())
+            if err != nil {
+                fmt.Println(T("msg.ongeldige_rating"))
+                continue
+            }
+            printBonusTable(os.Stdout, config.Rating.Bonus, ownRating)
+
+        case "15", "16", "17":
+            if config.Database.File == "" {
+                fmt.Println(T("msg.db_uit"))
+                continue
+            }
+            db, err := loadRatingDB(config.Database.File)
+            if err != nil {
+                fmt.Println(T("fout.db"), err)
+                continue
+            }
+            switch choice {
+            case "15":
+                allResults := readAllResults(currentRound)
+                playerData := buildRatingData(copyPlayers(players), allResults, startRatings(players), activeRatingSystem())
+                tournament := tournamentID()
+                err = commitRatingDB(db, playerData, tournament, time.Now().Format("2006-01-02"))
+                if err == nil {
+                    err = saveRatingDB(config.Database.File, db)
+                }
+                if err != nil {
+                    fmt.Println(T("fout.db"), err)
+                } else {
+                    fmt.Println(T("msg.db_vastgelegd", len(playerData), config.Database.File, tournament))
+                }
+            case "16":
+                printRatingLadder(os.Stdout, db)
+            case "17":
+                fmt.Print(T("vraag.spelernaam"))
+                if filename, err := generateRatingGraph(db, leesRegel()); err != nil {
+                    fmt.Println(T("fout.db"), err)
+                } else {
+                    fmt.Println(T("msg.grafiek_gegenereerd", filename))
+                }
+            }
+
         default:
-            fmt.Println("Ongeldige keuze")
+            fmt.Println(T("menu.ongeldig"))
         }
     }
 }
@@ -0,0 +1,119 @@
+package main
+
+import (
+    "fmt"
+    "math"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+// Oud toernooi (input.txt, rondeN.txt en rondeN_status.txt) inlezen en de volledige geschiedenis
+// herberekenen. Geeft het toernooi terug samen met een lijst van gevonden inconsistenties.
+func importLegacy(dir string) (Toernooi, []string, error) {
+    var problems []string
+
+    initial, err := readPlayers(filepath.Join(dir, "input.txt"))
+    if err != nil {
+        return Toernooi{}, nil, err
+    }
+    known := make(map[string]bool)
+    for _, p := range initial {
+        known[p.Name] = true
+    }
+
+    // Alle rondebestanden inlezen tot het eerste ontbrekende
+    var allResults [][]Result
+    for r := 1; ; r++ {
+        filename := filepath.Join(dir, fmt.Sprintf("ronde%d.txt", r))
+        if _, err := os.Stat(filename); err != nil {
+            break
+        }
+        results, err := readRoundResults(filename)
+        if err != nil {
+            return Toernooi{}, nil, err
+        }
+        problems = append(problems, checkRoundResults(r, results, known)...)
+        allResults = append(allResults, results)
+    }
+
+    // Herberekende stand vergelijken met de opgeslagen statusbestanden
+    standings := replayRounds(initial, allResults)
+    for r := range allResults {
+        statusFile := filepath.Join(dir, fmt.Sprintf("ronde%d_status.txt", r+1))
+        if _, err := os.Stat(statusFile); err != nil {
+            problems = append(problems, T("import.geen_status", r+1))
+            continue
+        }
+        status, err := readStatusFile(statusFile)
+        if err != nil {
+            return Toernooi{}, nil, err
+        }
+        problems = append(problems, compareStatus(r+1, standings[r], status)...)
+    }
+
+    return buildToernooi(initial, allResults), problems, nil
+}
+
+// Controleer de uitslagen van een ronde op onbekende spelers en dubbele pairings
+func checkRoundResults(round int, results []Result, known map[string]bool) []string {
+    var problems []string
+    seen := make(map[string]bool)
+    for _, result := range results {
+        for _, name := range []string{result.Player1, result.Player2} {
+            if name == "Bye" {
+                continue
+            }
+            if !known[name] {
+                problems = append(problems, T("import.onbekende_speler", round, name))
+            }
+            if seen[name] {
+                problems = append(problems, T("import.dubbel", round, name))
+            }
+            seen[name] = true
+        }
+        if result.Player2 != "Bye" && result.Score1 == 0 && result.Score2 == 0 {
+            problems = append(problems, T("import.nul_nul", round, result.Player1, result.Player2))
+        }
+    }
+    return problems
+}
+
+// Vergelijk de herberekende stand met de inhoud van een statusbestand
+func compareStatus(round int, replayed []Player, status []Player) []string {
+    var problems []string
+    byName := make(map[string]Player)
+    for _, p := range status {
+        byName[p.Name] = p
+    }
+    for _, p := range replayed {
+        s, ok := byName[p.Name]
+        if !ok {
+            problems = append(problems, T("import.ontbreekt", round, p.Name))
+            continue
+        }
+        delete(byName, p.Name)
+        if s.Punten != p.Punten {
+            problems = append(problems, T("import.punten", round, p.Name, s.Punten, p.Punten))
+        }
+        if s.Matchscore != p.Matchscore {
+            problems = append(problems, T("import.matchscore", round, p.Name, s.Matchscore, p.Matchscore))
+        }
+        if s.RoundsPlayed != p.RoundsPlayed {
+            problems = append(problems, T("import.gespeeld", round, p.Name, s.RoundsPlayed, p.RoundsPlayed))
+        }
+        if math.Abs(s.RatOppTotal-p.RatOppTotal) > 0.01 {
+            problems = append(problems, T("import.ratopp", round, p.Name, s.RatOppTotal, p.RatOppTotal))
+        }
+        if strings.Join(s.Opponents, ";") != strings.Join(p.Opponents, ";") {
+            problems = append(problems, T("import.tegenstanders",
+                round, p.Name, strings.Join(s.Opponents, ";"), strings.Join(p.Opponents, ";")))
+        }
+    }
+    for _, s := range status {
+        if _, ok := byName[s.Name]; ok {
+            problems = append(problems, T("import.niet_in_input", round, s.Name))
+        }
+    }
+    return problems
+}
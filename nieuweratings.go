@@ -0,0 +1,77 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "strings"
+)
+
+// Ratingwijziging van één speler over het toernooi
+type RatingUpdate struct {
+    Name       string  `json:"naam"`
+    Level      int     `json:"level"`
+    OldRating  int     `json:"oude_rating"`
+    NewRating  int     `json:"nieuwe_rating"`
+    Change     int     `json:"wijziging"`
+    Games      int     `json:"partijen"`      // Partijen na dit toernooi
+    RD         float64 `json:"rd,omitempty"`  // Glicko-2: deviatie en volatiliteit na het toernooi
+    Volatility float64 `json:"vol,omitempty"`
+}
+
+// Spelersregel in het formaat van input.txt, met de nieuwe rating en de bijgewerkte optionele velden
+func playerLine(pd PlayerData) string {
+    fields := []string{pd.Name, fmt.Sprint(pd.Level), fmt.Sprint(pd.NewRating), fmt.Sprintf("partijen=%d", pd.Games)}
+    if pd.Age > 0 {
+        fields = append(fields, fmt.Sprintf("leeftijd=%d", pd.Age))
+    }
+    if pd.Provisional && !pd.Estimated {
+        fields = append(fields, "voorlopig=1") // Nog geen partijen om een rating uit te schatten
+    }
+    if pd.NewRD > 0 {
+        fields = append(fields, fmt.Sprintf("rd=%.1f", pd.NewRD), fmt.Sprintf("vol=%.6f", pd.NewVolatility))
+    }
+    return strings.Join(fields, "   ") // Drie spaties, zoals input.txt
+}
+
+// Nieuwe spelerslijst en verschillen wegschrijven:
+// new_ratings.txt (formaat van input.txt, klaar voor het volgende toernooi), rating_update.html en rating_update.json
+func generateRatingUpdate(playerData []PlayerData) error {
+    var lines strings.Builder
+    var updates []RatingUpdate
+    for _, pd := range playerData {
+        lines.WriteString(playerLine(pd) + "\n")
+        updates = append(updates, RatingUpdate{
+            Name:       pd.Name,
+            Level:      pd.Level,
+            OldRating:  pd.InitialRating,
+            NewRating:  pd.NewRating,
+            Change:     pd.TotalAdd,
+            Games:      pd.Games,
+            RD:         pd.NewRD,
+            Volatility: pd.NewVolatility,
+        })
+    }
+    if err := os.WriteFile("new_ratings.txt", []byte(lines.String()), 0644); err != nil {
+        return err
+    }
+
+    t, err := loadTemplate(nil, "rating_update.html")
+    if err != nil {
+        return err
+    }
+    file, err := os.Create("rating_update.html")
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+    if err := t.Execute(file, updates); err != nil {
+        return err
+    }
+
+    data, err := json.MarshalIndent(updates, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile("rating_update.json", data, 0644)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+    "encoding/json"
+    "os"
+)
+
+// Eén prijswinnaar binnen een categorie
+type PrizeWinner struct {
+    Place  int     `json:"plaats"` // Plaats binnen de categorie vanaf 1; bij gedeelde plaatsen de eerste
+    Name   string  `json:"naam"`
+    Level  int     `json:"level"`
+    Rating int     `json:"rating"`
+    Punten int     `json:"punten"`
+    Amount float64 `json:"bedrag"`
+    Shared int     `json:"gedeeld_door,omitempty"` // Aantal spelers dat de bedragen deelt (alleen bij "split")
+}
+
+// Winnaars van één categorie
+type PrizeList struct {
+    Category string        `json:"categorie"`
+    Winners  []PrizeWinner `json:"winnaars"`
+}
+
+// Valt een speler in een categorie (Level, rating bij de start en vlag). Bij "per_round" is p.Rating al
+// bijgewerkt, dus de startrating wordt apart meegegeven.
+func inCategory(p Player, rating int, category PrizeCategory, flags map[string][]string) bool {
+    if category.LevelMin != 0 && p.Level < category.LevelMin {
+        return false
+    }
+    if category.LevelMax != 0 && p.Level > category.LevelMax {
+        return false
+    }
+    if category.RatingMin != 0 && rating < category.RatingMin {
+        return false
+    }
+    if category.RatingMax != 0 && rating > category.RatingMax {
+        return false
+    }
+    if category.Flag == "" {
+        return true
+    }
+    for _, name := range flags[category.Flag] {
+        if name == p.Name {
+            return true
+        }
+    }
+    return false
+}
+
+// Prijzen verdelen over de eindstand (volgorde van sortPlayers), categorie per categorie.
+// ratings bevat de rating van elke speler bij de start van het toernooi.
+func buildPrizes(standings []Player, ratings map[string]int, prizes PrizeConfig) []PrizeList {
+    won := make(map[string]bool)
+    var lists []PrizeList
+    for _, category := range prizes.Categories {
+        var eligible []Player
+        for _, p := range standings {
+            if inCategory(p, ratings[p.Name], category, prizes.Flags) && !(prizes.OnePrize && won[p.Name]) {
+                eligible = append(eligible, p)
+            }
+        }
+
+        list := PrizeList{Category: category.Name, Winners: []PrizeWinner{}}
+        for place := 0; place < len(category.Amounts) && place < len(eligible); {
+            // Bij "split" vormen spelers met evenveel Punten één groep die de bedragen van hun plaatsen deelt
+            group := 1
+            if prizes.Ties == "split" {
+                for place+group < len(eligible) && eligible[place+group].Punten == eligible[place].Punten {
+                    group++
+                }
+            }
+            total := 0.0
+            for i := place; i < place+group && i < len(category.Amounts); i++ {
+                total += category.Amounts[i]
+            }
+            for _, p := range eligible[place : place+group] {
+                winner := PrizeWinner{
+                    Place:  place + 1,
+                    Name:   p.Name,
+                    Level:  p.Level,
+                    Rating: ratings[p.Name],
+                    Punten: p.Punten,
+                    Amount: total / float64(group),
+                }
+                if group > 1 {
+                    winner.Shared = group
+                }
+                list.Winners = append(list.Winners, winner)
+                won[p.Name] = true
+            }
+            place += group
+        }
+        lists = append(lists, list)
+    }
+    return lists
+}
+
+// Prijzenlijst wegschrijven als prijzen.html en prijzen.json
+func generatePrizes(lists []PrizeList) error {
+    t, err := loadTemplate(nil, "prijzen.html")
+    if err != nil {
+        return err
+    }
+
+    file, err := os.Create("prijzen.html")
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+    if err := t.Execute(file, lists); err != nil {
+        return err
+    }
+
+    data, err := json.MarshalIndent(lists, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile("prijzen.json", data, 0644)
+}
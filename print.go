@@ -0,0 +1,55 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "sort"
+    "strings"
+)
+
+// Regel uit de alfabetische pairinglijst
+type PairingEntry struct {
+    Name     string
+    Board    int
+    Opponent string
+    Seat     int // 1 = eerste speler (begint), 2 = tweede speler
+}
+
+// Alfabetische pairinglijst maken zodat spelers snel hun bord vinden
+func alphabeticalPairings(matches []Match) []PairingEntry {
+    var entries []PairingEntry
+    for _, match := range matches {
+        entries = append(entries, PairingEntry{Name: match.Player1.Name, Board: match.Board, Opponent: match.Player2.Name, Seat: 1})
+        if match.Player2.Name != "Bye" {
+            entries = append(entries, PairingEntry{Name: match.Player2.Name, Board: match.Board, Opponent: match.Player1.Name, Seat: 2})
+        }
+    }
+    sort.Slice(entries, func(i, j int) bool {
+        return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+    })
+    return entries
+}
+
+// Printversie van een ronde genereren: één scoreslip per bord en een alfabetische pairinglijst
+func generatePrintHTML(round int, matches []Match) error {
+    // Zelfde bordvolgorde als rondeX.txt en rondeX.html
+    sortByBoard(matches)
+    t, err := loadTemplate(nil, "print.html")
+    if err != nil {
+        return err
+    }
+
+    filename := fmt.Sprintf("ronde%d_print.html", round)
+    file, err := os.Create(filename)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    data := struct {
+        Round    int
+        Matches  []Match
+        Pairings []PairingEntry
+    }{Round: round, Matches: matches, Pairings: alphabeticalPairings(matches)}
+    return t.Execute(file, data)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "html/template"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "text/tabwriter"
+)
+
+// Eén vastgelegd toernooi in de ratinghistorie van een speler; de velden geven de stand na het toernooi
+type RatingEntry struct {
+    Date        string  `json:"datum"` // JJJJ-MM-DD
+    Tournament  string  `json:"toernooi"`
+    OldRating   int     `json:"oude_rating"`
+    Rating      int     `json:"rating"`
+    Change      int     `json:"wijziging"`
+    Games       int     `json:"partijen"` // Totaal aantal partijen na het toernooi
+    RD          float64 `json:"rd,omitempty"`
+    Volatility  float64 `json:"vol,omitempty"`
+    Provisional bool    `json:"voorlopig,omitempty"` // Nog geen partijen om een rating uit te schatten
+}
+
+// Speler in de ratingdatabase; de huidige rating is die van de laatste regel in History
+type DBPlayer struct {
+    Name    string        `json:"naam"`
+    Level   int           `json:"level"`
+    Age     int           `json:"leeftijd,omitempty"`
+    History []RatingEntry `json:"historie"`
+}
+
+// Ratingdatabase: één JSON-bestand met alle spelers, alfabetisch
+type RatingDB struct {
+    Players []DBPlayer `json:"spelers"`
+}
+
+// Database inlezen; een ontbrekend bestand is een lege database
+func loadRatingDB(filename string) (*RatingDB, error) {
+    db := &RatingDB{}
+    data, err := os.ReadFile(filename)
+    if os.IsNotExist(err) {
+        return db, nil
+    } else if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(data, db); err != nil {
+        return nil, err
+    }
+    return db, nil
+}
+
+// Database wegschrijven via een tijdelijk bestand, zodat een onderbroken schrijfactie het oude bestand heel laat
+func saveRatingDB(filename string, db *RatingDB) error {
+    sort.Slice(db.Players, func(i, j int) bool { return db.Players[i].Name < db.Players[j].Name })
+    data, err := json.MarshalIndent(db, "", "  ")
+    if err != nil {
+        return err
+    }
+    tmp := filename + ".tmp"
+    if err := os.WriteFile(tmp, data, 0644); err != nil {
+        return err
+    }
+    return os.Rename(tmp, filename)
+}
+
+func (db *RatingDB) find(name string) *DBPlayer {
+    for i := range db.Players {
+        if db.Players[i].Name == name {
+            return &db.Players[i]
+        }
+    }
+    return nil
+}
+
+// Plaats van een toernooi in de historie, of len(History) als het nog niet is vastgelegd
+func (p *DBPlayer) entryIndex(tournament string) int {
+    for i, entry := range p.History {
+        if entry.Tournament == tournament {
+            return i
+        }
+    }
+    return len(p.History)
+}
+
+// ID waaronder dit toernooi in de database staat: uit config.json, anders de naam van de huidige map
+func tournamentID() string {
+    if config.Database.Tournament != "" {
+        return config.Database.Tournament
+    }
+    dir, err := os.Getwd()
+    if err != nil {
+        return "toernooi"
+    }
+    return filepath.Base(dir)
+}
+
+// Ratings bij de start van het toernooi uit de database halen. Is het toernooi al vastgelegd, dan geldt de stand
+// van daarvoor, zodat opnieuw berekenen hetzelfde resultaat geeft. Geeft het aantal bijgewerkte spelers terug.
+func applyRatingDB(db *RatingDB, players []Player, tournament string) int {
+    count := 0
+    for i := range players {
+        p := db.find(players[i].Name)
+        if p == nil {
+            continue
+        }
+        idx := p.entryIndex(tournament)
+        if idx == 0 {
+            continue // Eerste toernooi van deze speler: de waarden uit input.txt gelden
+        }
+        prev := p.History[idx-1]
+        players[i].Rating = prev.Rating
+        players[i].Games = prev.Games
+        players[i].Provisional = prev.Provisional // Rating uit de database is vast, ook als input.txt nog 0 heeft
+        if prev.RD > 0 {
+            players[i].RD, players[i].Volatility = prev.RD, prev.Volatility
+        }
+        if players[i].Age == 0 {
+            players[i].Age = p.Age
+        }
+        count++
+    }
+    return count
+}
+
+// Nieuwe ratings van dit toernooi vastleggen. Een eerder vastgelegde versie van hetzelfde toernooi wordt vervangen,
+// behalve als er voor een speler al een later toernooi in de historie staat.
+func commitRatingDB(db *RatingDB, playerData []PlayerData, tournament string, date string) error {
+    for _, pd := range playerData {
+        if p := db.find(pd.Name); p != nil && p.entryIndex(tournament) < len(p.History)-1 {
+            return errorT("fout.db_later", pd.Name, tournament)
+        }
+    }
+    for _, pd := range playerData {
+        p := db.find(pd.Name)
+        if p == nil {
+            db.Players = append(db.Players, DBPlayer{Name: pd.Name})
+            p = &db.Players[len(db.Players)-1]
+        }
+        p.Level = pd.Level
+        if pd.Age > 0 {
+            p.Age = pd.Age
+        }
+        p.History = append(p.History[:p.entryIndex(tournament)], RatingEntry{
+            Date:        date,
+            Tournament:  tournament,
+            OldRating:   pd.InitialRating,
+            Rating:      pd.NewRating,
+            Change:      pd.TotalAdd,
+            Games:       pd.Games,
+            RD:          pd.NewRD,
+            Volatility:  pd.NewVolatility,
+            Provisional: pd.Provisional && !pd.Estimated,
+        })
+    }
+    return nil
+}
+
+// Ranglijst van alle spelers in de database, hoogste rating eerst
+func printRatingLadder(out io.Writer, db *RatingDB) {
+    ladder := make([]DBPlayer, 0, len(db.Players))
+    for _, p := range db.Players {
+        if len(p.History) > 0 {
+            ladder = append(ladder, p)
+        }
+    }
+    current := func(p DBPlayer) RatingEntry { return p.History[len(p.History)-1] }
+    sort.SliceStable(ladder, func(i, j int) bool { return current(ladder[i]).Rating > current(ladder[j]).Rating })
+
+    w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+    fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", T("doc.nr"), T("doc.naam"), T("doc.level"), T("doc.rating"),
+        T("doc.partijen"), T("doc.toernooien"), T("doc.laatste_toernooi"))
+    for i, p := range ladder {
+        last := current(p)
+        fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%d\t%s (%s)\n", i+1, p.Name, p.Level, last.Rating, last.Games,
+            len(p.History), last.Tournament, last.Date)
+    }
+    w.Flush()
+}
+
+// Ratingverloop van één speler als grafiek met de historie eronder; geeft de bestandsnaam terug
+func generateRatingGraph(db *RatingDB, name string) (string, error) {
+    p := db.find(name)
+    if p == nil || len(p.History) == 0 {
+        return "", errorT("fout.db_onbekende_speler", name)
+    }
+    labels := []string{T("doc.start")}
+    points := []string{T("doc.start")}
+    series := chartSeries{Name: p.Name, Values: []float64{float64(p.History[0].OldRating)}}
+    for _, entry := range p.History {
+        labels = append(labels, entry.Date)
+        points = append(points, entry.Tournament)
+        series.Values = append(series.Values, float64(entry.Rating))
+    }
+
+    t, err := loadTemplate(nil, "ratinggrafiek.html")
+    if err != nil {
+        return "", err
+    }
+    filename := "ratinggrafiek_" + playerSlugs([]Player{{Name: p.Name}})[p.Name] + ".html"
+    file, err := os.Create(filename)
+    if err != nil {
+        return "", err
+    }
+    defer file.Close()
+    data := struct {
+        Player DBPlayer
+        Chart  template.HTML
+    }{
+        Player: *p,
+        Chart:  labeledChartSVG(T("doc.ratingverloop", p.Name), labels, points, []chartSeries{series}, false),
+    }
+    return filename, t.Execute(file, data)
+}
@@ -0,0 +1,481 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "math"
+    "text/tabwriter"
+)
+
+// Eén gespeelde partij vanuit een speler, als invoer voor een ratingsysteem (byes tellen niet mee)
+type RatedGame struct {
+    Opponent       Player
+    OpponentRating int    // Rating van de tegenstander waarmee gerekend wordt
+    Outcome        string // "w", "d" of "l"
+    Score          int    // Eigen score, bv. 6 bij 6-2
+    OpponentScore  int
+}
+
+// Ratingwijziging door één partij
+type GameChange struct {
+    Expected float64 // Verwachte score volgens de ratings (0 tot 1)
+    Actual   float64 // Behaalde score: 1, 0.5 of 0
+    Factor   float64 // Weging op het scoreverschil, 0 als die niet gebruikt wordt
+    Change   float64
+}
+
+// Resultaat van een ratingsysteem voor één speler over het hele toernooi
+type RatingChange struct {
+    Games         []GameChange // Zelfde volgorde als de partijen
+    Total         int          // Totale wijziging, afgerond
+    RD            float64      // Glicko-2: deviatie en volatiliteit bij de start en erna (0 bij andere systemen)
+    Volatility    float64
+    NewRD         float64
+    NewVolatility float64
+    Estimated     bool // Voorlopige speler: nieuwe rating geschat uit de performance
+}
+
+// RatingSystem berekent de ratingwijziging van een speler uit diens partijen
+type RatingSystem interface {
+    Name() string
+    Rate(player Player, rating int, games []RatedGame) RatingChange
+}
+
+// Beschikbare ratingsystemen voor "rating": {"system": ...} in config.json
+var ratingSystems = map[string]func(cfg RatingConfig) RatingSystem{
+    "bonus":   func(cfg RatingConfig) RatingSystem { return bonusSystem{cfg: cfg.Bonus} },
+    "elo":     func(cfg RatingConfig) RatingSystem { return eloSystem{cfg: cfg.Elo} },
+    "glicko2": func(cfg RatingConfig) RatingSystem { return glicko2System{cfg: cfg.Glicko2} },
+}
+
+// Ratingsysteem volgens de configuratie, eventueel met weging op het scoreverschil
+func activeRatingSystem() RatingSystem {
+    system := ratingSystems[config.Rating.System](config.Rating)
+    if config.Rating.Margin.Curve != "none" {
+        system = marginSystem{inner: system, cfg: config.Rating.Margin}
+    }
+    return system
+}
+
+// Curves voor de weging op het scoreverschil
+var marginCurves = map[string]func(margin float64) float64{
+    "linear": func(m float64) float64 { return m },
+    "sqrt":   math.Sqrt,
+    "log":    func(m float64) float64 { return math.Log(m + 1) },
+}
+
+// Schaalt de wijziging van elke gewonnen of verloren partij van een ander systeem met het scoreverschil
+type marginSystem struct {
+    inner RatingSystem
+    cfg   MarginConfig
+}
+
+func (m marginSystem) Name() string {
+    return m.inner.Name() + "+margin:" + m.cfg.Curve
+}
+
+// Factor voor een partij; remises (verschil 0) houden factor 1
+func (m marginSystem) factor(game RatedGame) float64 {
+    margin := math.Abs(float64(game.Score - game.OpponentScore))
+    if margin == 0 {
+        return 1
+    }
+    return math.Min(m.cfg.Cap, m.cfg.Scale*marginCurves[m.cfg.Curve](margin))
+}
+
+func (m marginSystem) Rate(player Player, rating int, games []RatedGame) RatingChange {
+    change := m.inner.Rate(player, rating, games)
+    total := 0.0
+    for i := range change.Games {
+        change.Games[i].Factor = m.factor(games[i])
+        change.Games[i].Change *= change.Games[i].Factor
+        total += change.Games[i].Change
+    }
+    change.Total = int(math.Round(total))
+    return change
+}
+
+// Partij van een speler in een ratingperiode, met de ronde (vanaf 0) waarin ze gespeeld is
+type periodGame struct {
+    Round int
+    Game  RatedGame
+}
+
+// Spelers als beginstand voor de ratingberekening, met de rating bij de start.
+// RoundRatings wordt door ratePeriod gevuld met de rating bij het begin van elke afgerekende ronde.
+func ratingState(players []Player, initialRatings map[string]int) map[string]Player {
+    state := make(map[string]Player)
+    for _, p := range players {
+        p.Rating = initialRatings[p.Name]
+        p.RoundRatings = nil
+        state[p.Name] = p
+    }
+    return state
+}
+
+// Ratingperiodes: het hele toernooi in één keer ("batch") of elke ronde apart ("per_round")
+func ratingPeriods(rounds int) [][]int {
+    if config.Rating.Mode != "per_round" || rounds == 0 {
+        var all []int
+        for r := 0; r < rounds; r++ {
+            all = append(all, r)
+        }
+        return [][]int{all}
+    }
+    var periods [][]int
+    for r := 0; r < rounds; r++ {
+        periods = append(periods, []int{r})
+    }
+    return periods
+}
+
+// Rating van een speler uit state bij het begin van ronde r (0-based)
+func ratingInRound(p Player, r int) int {
+    if r < len(p.RoundRatings) {
+        return p.RoundRatings[r]
+    }
+    return p.Rating
+}
+
+// Partijen per speler in de rondes van een periode, tegen de ratings uit state in die ronde; byes tellen niet mee
+func periodGames(state map[string]Player, allResults [][]Result, rounds []int) map[string][]periodGame {
+    games := make(map[string][]periodGame)
+    for _, r := range rounds {
+        for _, result := range allResults[r] {
+            if result.Player2 == "Bye" {
+                continue
+            }
+            p1, p2 := state[result.Player1], state[result.Player2]
+            games[result.Player1] = append(games[result.Player1], periodGame{Round: r, Game: RatedGame{
+                Opponent: p2, OpponentRating: ratingInRound(p2, r), Outcome: getMatchOutcome(result.Player1, result),
+                Score: result.Score1, OpponentScore: result.Score2,
+            }})
+            games[result.Player2] = append(games[result.Player2], periodGame{Round: r, Game: RatedGame{
+                Opponent: p1, OpponentRating: ratingInRound(p1, r), Outcome: getMatchOutcome(result.Player2, result),
+                Score: result.Score2, OpponentScore: result.Score1,
+            }})
+        }
+    }
+    return games
+}
+
+// Eén ratingperiode afrekenen: alle spelers tegelijk met de ratings van het begin van de periode,
+// daarna rating, partijen en bij Glicko-2 deviatie en volatiliteit in state bijwerken.
+// Voorlopige spelers krijgen de performance over alle rondes tot nu toe als nieuwe rating, met elke
+// tegenstander op de rating van de ronde waarin gespeeld werd (zoals bij "batch").
+func ratePeriod(state map[string]Player, allResults [][]Result, rounds []int, system RatingSystem) (map[string]RatingChange, map[string][]periodGame) {
+    for name, p := range state {
+        for _, r := range rounds {
+            if len(p.RoundRatings) == r {
+                p.RoundRatings = append(p.RoundRatings, p.Rating)
+            }
+        }
+        state[name] = p
+    }
+    games := periodGames(state, allResults, rounds)
+    var sofar []int
+    if len(rounds) > 0 {
+        for r := 0; r <= rounds[len(rounds)-1]; r++ {
+            sofar = append(sofar, r)
+        }
+    }
+    allGames := periodGames(state, allResults, sofar)
+    changes := make(map[string]RatingChange)
+    for name, p := range state {
+        var rated []RatedGame
+        for _, g := range games[name] {
+            rated = append(rated, g.Game)
+        }
+        change := system.Rate(p, p.Rating, rated)
+        if p.Provisional {
+            change = provisionalChange(p, change, allGames[name])
+        } else {
+            weighProvisionalOpponents(&change, rated)
+        }
+        changes[name] = change
+    }
+    for name, change := range changes {
+        p := state[name]
+        p.Rating += change.Total
+        p.Games += len(games[name])
+        if change.NewRD > 0 {
+            p.RD, p.Volatility = change.NewRD, change.NewVolatility
+        }
+        state[name] = p
+    }
+    return changes, games
+}
+
+// Nieuwe rating van een voorlopige speler: de performance tegen spelers met een vaste rating.
+// De partijen zelf geven dan geen wijziging; zonder zulke partijen blijft de rating staan.
+func provisionalChange(p Player, change RatingChange, games []periodGame) RatingChange {
+    for i := range change.Games {
+        change.Games[i].Change = 0
+    }
+    change.Total = 0
+    total, score, n := 0.0, 0.0, 0
+    for _, g := range games {
+        if g.Game.Opponent.Provisional {
+            continue
+        }
+        total += float64(g.Game.OpponentRating)
+        score += outcomeScore(g.Game.Outcome)
+        n++
+    }
+    if n == 0 {
+        return change
+    }
+    avgOpp := total / float64(n)
+    estimate := avgOpp + float64(fideDPFor(score/float64(n)))
+    if config.Rating.Provisional.Estimate == "tpr" {
+        estimate = avgOpp + 400*(2*score-float64(n))/float64(n)
+    }
+    change.Total = int(math.Round(estimate)) - p.Rating
+    change.Estimated = true
+    return change
+}
+
+// Wijziging van partijen tegen voorlopige spelers dempen of weglaten volgens opponent_weight
+func weighProvisionalOpponents(change *RatingChange, games []RatedGame) {
+    weight := config.Rating.Provisional.OpponentWeight
+    if weight == 1 {
+        return
+    }
+    weighed := false
+    for i, game := range games {
+        if game.Opponent.Provisional {
+            change.Games[i].Change *= weight
+            weighed = true
+        }
+    }
+    if !weighed {
+        return
+    }
+    total := 0.0
+    for _, game := range change.Games {
+        total += game.Change
+    }
+    change.Total = int(math.Round(total))
+}
+
+// De eigen bonustabel (getBonus): vaste winst of verlies per partij afhankelijk van het ratingverschil
+type bonusSystem struct {
+    cfg BonusConfig
+}
+
+func (b bonusSystem) Name() string {
+    return "bonus"
+}
+
+func (b bonusSystem) Rate(player Player, rating int, games []RatedGame) RatingChange {
+    var change RatingChange
+    total := 0.0
+    for _, game := range games {
+        bonus := float64(getBonus(b.cfg, game.OpponentRating, rating, game.Outcome))
+        // De verwachte score is ter informatie (Elo-curve); de bonustabel gebruikt ze niet
+        change.Games = append(change.Games, GameChange{
+            Expected: eloExpected(rating, game.OpponentRating),
+            Actual:   outcomeScore(game.Outcome),
+            Change:   bonus,
+        })
+        total += bonus
+    }
+    change.Total = int(math.Round(total))
+    return change
+}
+
+// Volledige bonustabel voor een eigen rating: één regel per reeks tegenstanders met dezelfde winst, remise en verlies
+func printBonusTable(out io.Writer, cfg BonusConfig, ownRating int) {
+    fmt.Fprintln(out, T("msg.bonustabel", ownRating, cfg.Range, cfg.MaxGain, cfg.LossPercent, cfg.DrawRule, cfg.Rounding))
+    w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+    fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", T("doc.tegenstander"), T("doc.verschil"), T("doc.winst"), T("doc.remise"), T("doc.verlies"))
+
+    low, high := ownRating-cfg.Range, ownRating+cfg.Range
+    row := func(from, to int) {
+        opponents := fmt.Sprintf("%d-%d", from, to)
+        diff := fmt.Sprintf("%+d..%+d", from-ownRating, to-ownRating)
+        if from == low {
+            opponents, diff = fmt.Sprintf("<= %d", to), fmt.Sprintf("<= %+d", to-ownRating)
+        } else if to == high {
+            opponents, diff = fmt.Sprintf(">= %d", from), fmt.Sprintf(">= %+d", from-ownRating)
+        }
+        fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t\n", opponents, diff,
+            getBonus(cfg, from, ownRating, "w"), getBonus(cfg, from, ownRating, "d"), getBonus(cfg, from, ownRating, "l"))
+    }
+    values := func(opp int) [3]int {
+        return [3]int{getBonus(cfg, opp, ownRating, "w"), getBonus(cfg, opp, ownRating, "d"), getBonus(cfg, opp, ownRating, "l")}
+    }
+    start := low
+    for opp := low + 1; opp <= high; opp++ {
+        if values(opp) != values(start) {
+            row(start, opp-1)
+            start = opp
+        }
+    }
+    row(start, high)
+    w.Flush()
+}
+
+// Klassieke Elo: verwachte score uit de logistische curve, wijziging K * (score - verwachting) per partij
+type eloSystem struct {
+    cfg EloConfig
+}
+
+func (e eloSystem) Name() string {
+    return "elo"
+}
+
+// K-factor van een speler volgens de eerste passende regel, met rating en partijen aan het begin van de ratingperiode
+func (e eloSystem) kFactor(player Player, rating int) float64 {
+    for _, rule := range e.cfg.Rules {
+        if rule.RatingBelow != 0 && rating >= rule.RatingBelow {
+            continue
+        }
+        if rule.RatingAtLeast != 0 && rating < rule.RatingAtLeast {
+            continue
+        }
+        if rule.GamesBelow != 0 && player.Games >= rule.GamesBelow {
+            continue
+        }
+        if rule.AgeBelow != 0 && (player.Age == 0 || player.Age >= rule.AgeBelow) {
+            continue
+        }
+        if rule.LevelBelow != 0 && player.Level >= rule.LevelBelow {
+            continue
+        }
+        return rule.K
+    }
+    return e.cfg.K
+}
+
+func (e eloSystem) Rate(player Player, rating int, games []RatedGame) RatingChange {
+    k := e.kFactor(player, rating)
+    var change RatingChange
+    total := 0.0
+    for _, game := range games {
+        expected := eloExpected(rating, game.OpponentRating)
+        actual := outcomeScore(game.Outcome)
+        delta := k * (actual - expected)
+        change.Games = append(change.Games, GameChange{Expected: expected, Actual: actual, Change: delta})
+        total += delta
+    }
+    change.Total = int(math.Round(total))
+    return change
+}
+
+// Verwachte score tegen een tegenstander volgens de logistische Elo-curve
+func eloExpected(rating int, opponentRating int) float64 {
+    return 1 / (1 + math.Pow(10, float64(opponentRating-rating)/400))
+}
+
+// Partijpunten van een uitkomst: winst 1, remise 0.5, verlies 0
+func outcomeScore(outcome string) float64 {
+    switch outcome {
+    case "w":
+        return 1
+    case "d":
+        return 0.5
+    }
+    return 0
+}
+
+// Schaalfactor tussen de Glicko-schaal (1500 ± RD) en de interne Glicko-2 schaal
+const glickoScale = 173.7178
+
+// Glicko-2 (Glickman): rating, deviatie en volatiliteit; één toernooi is één ratingperiode
+type glicko2System struct {
+    cfg Glicko2Config
+}
+
+func (g glicko2System) Name() string {
+    return "glicko2"
+}
+
+// Deviatie en volatiliteit van een speler, met de standaardwaarden voor nieuwe spelers
+func (g glicko2System) state(p Player) (rd float64, volatility float64) {
+    rd, volatility = p.RD, p.Volatility
+    if rd <= 0 {
+        rd = g.cfg.DefaultRD
+    }
+    if volatility <= 0 {
+        volatility = g.cfg.DefaultVolatility
+    }
+    return rd, volatility
+}
+
+// Weging van een partij naar de onzekerheid van de tegenstander
+func glickoG(phi float64) float64 {
+    return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func (g glicko2System) Rate(player Player, rating int, games []RatedGame) RatingChange {
+    rd, sigma := g.state(player)
+    mu := (float64(rating) - 1500) / glickoScale
+    phi := rd / glickoScale
+
+    // Zonder partijen groeit alleen de onzekerheid
+    if len(games) == 0 {
+        return RatingChange{RD: rd, Volatility: sigma, NewRD: glickoScale * math.Sqrt(phi*phi+sigma*sigma), NewVolatility: sigma}
+    }
+
+    weights := make([]float64, len(games))
+    expected := make([]float64, len(games))
+    actual := make([]float64, len(games))
+    vInv, sum := 0.0, 0.0
+    for i, game := range games {
+        oppRD, _ := g.state(game.Opponent)
+        muJ := (float64(game.OpponentRating) - 1500) / glickoScale
+        weights[i] = glickoG(oppRD / glickoScale)
+        expected[i] = 1 / (1 + math.Exp(-weights[i]*(mu-muJ)))
+        actual[i] = outcomeScore(game.Outcome)
+        vInv += weights[i] * weights[i] * expected[i] * (1 - expected[i])
+        sum += weights[i] * (actual[i] - expected[i])
+    }
+    v := 1 / vInv
+    delta := v * sum
+
+    // Nieuwe volatiliteit met het Illinois-algoritme
+    tau := g.cfg.Tau
+    a := math.Log(sigma * sigma)
+    f := func(x float64) float64 {
+        ex := math.Exp(x)
+        return ex*(delta*delta-phi*phi-v-ex)/(2*(phi*phi+v+ex)*(phi*phi+v+ex)) - (x-a)/(tau*tau)
+    }
+    A := a
+    var B float64
+    if delta*delta > phi*phi+v {
+        B = math.Log(delta*delta - phi*phi - v)
+    } else {
+        k := 1.0
+        for f(a-k*tau) < 0 {
+            k++
+        }
+        B = a - k*tau
+    }
+    fA, fB := f(A), f(B)
+    for math.Abs(B-A) > 0.000001 {
+        C := A + (A-B)*fA/(fB-fA)
+        fC := f(C)
+        if fC*fB <= 0 {
+            A, fA = B, fB
+        } else {
+            fA /= 2
+        }
+        B, fB = C, fC
+    }
+    newSigma := math.Exp(A / 2)
+
+    phiStar := math.Sqrt(phi*phi + newSigma*newSigma)
+    newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+
+    change := RatingChange{RD: rd, Volatility: sigma, NewRD: glickoScale * newPhi, NewVolatility: newSigma}
+    total := 0.0
+    for i := range games {
+        // Aandeel van elke partij in de nieuwe rating: phi'^2 * g * (s - E), terug naar de Glicko-schaal
+        delta := glickoScale * newPhi * newPhi * weights[i] * (actual[i] - expected[i])
+        change.Games = append(change.Games, GameChange{Expected: expected[i], Actual: actual[i], Change: delta})
+        total += delta
+    }
+    change.Total = int(math.Round(total))
+    return change
+}
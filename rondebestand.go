@@ -0,0 +1,234 @@
+package main
+
+import (
+    "bufio"
+    "encoding/csv"
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+)
+
+// Versieregel bovenaan rondebestanden in het nieuwe formaat. Bestanden zonder deze regel
+// worden gelezen als het oude formaat "Naam LVL 21 (1985 rating)   3-3   Naam LVL ...".
+const roundFileHeader = "#ZTRONDE v2"
+
+// Kolommen van een rondebestand in het nieuwe formaat (CSV, namen met komma's of aanhalingstekens worden gequote)
+var roundFileColumns = []string{"bord", "naam1", "level1", "rating1", "score", "naam2", "level2", "rating2"}
+
+// Eén pairing uit rondeX.txt, met bordnummer
+type roundLine struct {
+    Board   int
+    Name1   string
+    Level1  int
+    Rating1 int
+    Result  string // bv "3-3"
+    Name2   string
+    Level2  int
+    Rating2 int
+}
+
+// Spelerveld "Naam LVL 21 (1985 rating)" opsplitsen in naam, level en rating
+func parsePlayerField(field string) (string, int, int) {
+    // Laatste " LVL " zoeken zodat namen met "LVL" erin heel blijven
+    idx := strings.LastIndex(field, " LVL ")
+    if idx < 0 {
+        return field, 0, 0
+    }
+    var level, rating int
+    fmt.Sscanf(field[idx:], " LVL %d (%d rating)", &level, &rating)
+    return field[:idx], level, rating
+}
+
+// Bordnummer ("3: ") vooraan een regel afsplitsen; 0 als er geen bordnummer staat
+func splitBoardPrefix(line string) (int, string) {
+    idx := strings.Index(line, ": ")
+    if idx <= 0 {
+        return 0, line
+    }
+    board, err := strconv.Atoi(line[:idx])
+    if err != nil {
+        return 0, line
+    }
+    return board, line[idx+2:]
+}
+
+// Alle pairings uit rondeX.txt lezen, in het nieuwe of het oude formaat
+func readRoundLines(filename string) ([]roundLine, error) {
+    file, err := os.Open(filename)
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+
+    reader := bufio.NewReader(file)
+    first, err := reader.Peek(len(roundFileHeader))
+    if err == nil && string(first) == roundFileHeader {
+        return readRoundLinesV2(reader)
+    }
+    return readRoundLinesLegacy(reader)
+}
+
+// Oud formaat: velden gescheiden door drie spaties; regels zonder bordnummer krijgen hun volgnummer
+func readRoundLinesLegacy(reader *bufio.Reader) ([]roundLine, error) {
+    var lines []roundLine
+    scanner := bufio.NewScanner(reader)
+    for scanner.Scan() {
+        board, line := splitBoardPrefix(scanner.Text())
+        parts := strings.Split(line, "   ") // Drie spaties
+        if len(parts) != 3 {
+            continue
+        }
+        if board == 0 {
+            board = len(lines) + 1
+        }
+        rl := roundLine{Board: board, Result: parts[1]}
+        rl.Name1, rl.Level1, rl.Rating1 = parsePlayerField(parts[0])
+        rl.Name2, rl.Level2, rl.Rating2 = parsePlayerField(parts[2])
+        lines = append(lines, rl)
+    }
+    return lines, scanner.Err()
+}
+
+// Nieuw formaat: versieregel gevolgd door CSV-regels met vaste kolommen
+func readRoundLinesV2(reader *bufio.Reader) ([]roundLine, error) {
+    r := csv.NewReader(reader)
+    r.Comment = '#'
+    r.FieldsPerRecord = len(roundFileColumns)
+    r.TrimLeadingSpace = true
+
+    var lines []roundLine
+    for {
+        record, err := r.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, err
+        }
+        if record[0] == roundFileColumns[0] {
+            continue // Kolomkoppen
+        }
+        var rl roundLine
+        if rl.Board, err = strconv.Atoi(record[0]); err != nil {
+            line, _ := r.FieldPos(0)
+            return nil, errorT("fout.regel_bordnummer", line, record[0])
+        }
+        rl.Name1 = record[1]
+        rl.Level1, _ = strconv.Atoi(record[2])
+        rl.Rating1, _ = strconv.Atoi(record[3])
+        rl.Result = strings.ReplaceAll(record[4], " ", "")
+        rl.Name2 = record[5]
+        rl.Level2, _ = strconv.Atoi(record[6])
+        rl.Rating2, _ = strconv.Atoi(record[7])
+        lines = append(lines, rl)
+    }
+    return lines, nil
+}
+
+// Rondebestand schrijven in het nieuwe formaat, met versieregel en kolomkoppen
+func writeRoundLines(filename string, lines []roundLine) error {
+    file, err := os.Create(filename)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    if _, err := fmt.Fprintln(file, roundFileHeader); err != nil {
+        return err
+    }
+    w := csv.NewWriter(file)
+    w.Write(roundFileColumns)
+    for _, rl := range lines {
+        w.Write([]string{
+            strconv.Itoa(rl.Board),
+            rl.Name1, strconv.Itoa(rl.Level1), strconv.Itoa(rl.Rating1),
+            rl.Result,
+            rl.Name2, strconv.Itoa(rl.Level2), strconv.Itoa(rl.Rating2),
+        })
+    }
+    w.Flush()
+    return w.Error()
+}
+
+// Compacte uitslagen lezen uit rondeX_uitslagen.txt, één regel per bord: "3: 6-2"
+func readBoardResults(filename string) (map[int]string, error) {
+    file, err := os.Open(filename)
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+
+    results := make(map[int]string)
+    scanner := bufio.NewScanner(file)
+    lineNr := 0
+    for scanner.Scan() {
+        lineNr++
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        parts := strings.SplitN(line, ":", 2)
+        if len(parts) != 2 {
+            return nil, errorT("fout.regel_formaat", lineNr, line)
+        }
+        board, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+        if err != nil {
+            return nil, errorT("fout.regel_bordnummer", lineNr, parts[0])
+        }
+        score := strings.ReplaceAll(parts[1], " ", "")
+        if _, _, ok := parseScore(score); !ok {
+            return nil, errorT("fout.regel_score", lineNr, parts[1])
+        }
+        if _, dup := results[board]; dup {
+            return nil, errorT("fout.regel_dubbel_bord", lineNr, board)
+        }
+        results[board] = score
+    }
+    return results, scanner.Err()
+}
+
+// Score "6-2" omzetten naar twee getallen
+func parseScore(score string) (int, int, bool) {
+    scores := strings.Split(score, "-")
+    if len(scores) != 2 {
+        return 0, 0, false
+    }
+    score1, err1 := strconv.Atoi(scores[0])
+    score2, err2 := strconv.Atoi(scores[1])
+    if err1 != nil || err2 != nil {
+        return 0, 0, false
+    }
+    return score1, score2, true
+}
+
+// Uitslagen per bord samenvoegen met de pairings in rondeX.txt en het rondebestand herschrijven
+func mergeBoardResults(roundFile, resultsFile string) error {
+    lines, err := readRoundLines(roundFile)
+    if err != nil {
+        return err
+    }
+    results, err := readBoardResults(resultsFile)
+    if err != nil {
+        return err
+    }
+    for board := range results {
+        found := false
+        for _, rl := range lines {
+            if rl.Board == board {
+                found = true
+                break
+            }
+        }
+        if !found {
+            return errorT("fout.bord_bestaat_niet", board, roundFile)
+        }
+    }
+    for i, rl := range lines {
+        if score, ok := results[rl.Board]; ok {
+            lines[i].Result = score
+        }
+    }
+    return writeRoundLines(roundFile, lines)
+}
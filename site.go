@@ -0,0 +1,165 @@
+package main
+
+import (
+    "fmt"
+    "html/template"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// Gegevens voor één pagina van de website
+type sitePage struct {
+    Title  string
+    Rounds []int // Rondenummers voor de navigatie
+    Data   interface{}
+}
+
+// Bestandsnamen voor spelerpagina's: kleine letters, alleen letters en cijfers, uniek per speler
+func playerSlugs(players []Player) map[string]string {
+    // Alfabetische volgorde, zodat dezelfde speler altijd dezelfde bestandsnaam krijgt
+    var names []string
+    for _, p := range players {
+        names = append(names, p.Name)
+    }
+    sort.Strings(names)
+
+    slugs := make(map[string]string)
+    used := make(map[string]bool)
+    for _, name := range names {
+        var sb strings.Builder
+        for _, r := range strings.ToLower(name) {
+            if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+                sb.WriteRune(r)
+            } else if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "-") {
+                sb.WriteRune('-')
+            }
+        }
+        slug := strings.Trim(sb.String(), "-")
+        if slug == "" {
+            slug = "speler"
+        }
+        unique := slug
+        for n := 2; used[unique]; n++ {
+            unique = slug + "-" + strconv.Itoa(n)
+        }
+        used[unique] = true
+        slugs[name] = unique
+    }
+    return slugs
+}
+
+// Volledige statische website bouwen in dir: index, rondes, spelers, crosstable en rating overview
+func publishSite(dir string, players []Player, allResults [][]Result) error {
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        return err
+    }
+    css, err := readTemplateFile("site.css")
+    if err != nil {
+        return err
+    }
+    if err := os.WriteFile(filepath.Join(dir, "style.css"), []byte(css), 0644); err != nil {
+        return err
+    }
+
+    initial := resetPlayers(players)
+    slugs := playerSlugs(initial)
+    base, err := loadTemplate(template.FuncMap{
+        "spelerlink": func(name string) string { return "speler_" + slugs[name] + ".html" },
+    }, "site_layout.html", "historie.html")
+    if err != nil {
+        return err
+    }
+
+    var rounds []int
+    for r := range allResults {
+        rounds = append(rounds, r+1)
+    }
+    standings := replayRounds(initial, allResults)
+    finalStanding := initial
+    if len(standings) > 0 {
+        finalStanding = standings[len(standings)-1]
+    }
+
+    // Pagina schrijven met de basislayout en het blok "content" uit het templatebestand
+    writePage := func(filename, content string, page sitePage) error {
+        text, err := readTemplateFile(content)
+        if err != nil {
+            return err
+        }
+        t, err := base.Clone()
+        if err != nil {
+            return err
+        }
+        if _, err := t.Parse(text); err != nil {
+            return err
+        }
+        file, err := os.Create(filepath.Join(dir, filename))
+        if err != nil {
+            return err
+        }
+        defer file.Close()
+        page.Rounds = rounds
+        return t.ExecuteTemplate(file, "layout", page)
+    }
+
+    if err := writePage("index.html", "site_index.html", sitePage{
+        Title: T("doc.site_titel"),
+        Data: struct {
+            Players   []Player
+            Standings []Player
+            Charts    []template.HTML
+        }{Players: initial, Standings: finalStanding, Charts: progressionCharts(standings)},
+    }); err != nil {
+        return err
+    }
+
+    for r, results := range allResults {
+        if err := writePage(fmt.Sprintf("ronde%d.html", r+1), "site_ronde.html", sitePage{
+            Title: T("doc.ronde", r+1),
+            Data: struct {
+                Round     int
+                Results   []Result
+                Standings []Player
+            }{Round: r + 1, Results: results, Standings: standings[r]},
+        }); err != nil {
+            return err
+        }
+    }
+
+    if err := writePage("crosstable.html", "site_crosstable.html", sitePage{
+        Title: T("doc.crosstable"),
+        Data: struct {
+            Tiebreaks []string
+            Rows      []CrossRow
+        }{Tiebreaks: tiebreakLabels(), Rows: buildCrosstable(initial, allResults)},
+    }); err != nil {
+        return err
+    }
+
+    initialRatings := make(map[string]int)
+    for _, p := range initial {
+        initialRatings[p.Name] = p.Rating
+    }
+    system := activeRatingSystem()
+    ratingData := buildRatingData(copyPlayers(finalStanding), allResults, initialRatings, system)
+    for _, pd := range ratingData {
+        if err := writePage("speler_"+slugs[pd.Name]+".html", "site_speler.html", sitePage{
+            Title: pd.Name,
+            Data:  pd,
+        }); err != nil {
+            return err
+        }
+    }
+
+    return writePage("overview.html", "site_overview.html", sitePage{
+        Title: T("doc.rating_overview"),
+        Data: struct {
+            System  string
+            Margin  bool
+            Players []PlayerData
+        }{System: system.Name(), Margin: config.Rating.Margin.Curve != "none", Players: ratingData},
+    })
+}
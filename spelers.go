@@ -0,0 +1,34 @@
+package main
+
+import (
+    "html/template"
+    "os"
+    "path/filepath"
+)
+
+// Losse HTML-pagina per speler in dir, met dezelfde bestandsnamen als op de website
+func generatePlayerPages(dir string, players []Player, allResults [][]Result, initialRatings map[string]int) error {
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        return err
+    }
+    slugs := playerSlugs(players)
+    t, err := loadTemplate(template.FuncMap{
+        "spelerlink": func(name string) string { return "speler_" + slugs[name] + ".html" },
+    }, "speler.html", "historie.html")
+    if err != nil {
+        return err
+    }
+
+    for _, pd := range buildRatingData(players, allResults, initialRatings, activeRatingSystem()) {
+        file, err := os.Create(filepath.Join(dir, "speler_"+slugs[pd.Name]+".html"))
+        if err != nil {
+            return err
+        }
+        err = t.Execute(file, pd)
+        file.Close()
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
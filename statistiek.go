@@ -0,0 +1,141 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "math"
+    "os"
+)
+
+// Eén opvallende partij voor het statistiekenrapport
+type GameFact struct {
+    Round        int    `json:"ronde"`
+    Winner       string `json:"winnaar"`
+    Loser        string `json:"verliezer"`
+    WinnerRating int    `json:"rating_winnaar"`
+    LoserRating  int    `json:"rating_verliezer"`
+    Score        string `json:"score"`
+    Value        int    `json:"waarde"` // Ratingverschil bij een upset, score van de winnaar bij de hoogste score
+}
+
+// Langste reeks opeenvolgende overwinningen (byes tellen niet mee en onderbreken de reeks niet)
+type Streak struct {
+    Players []string `json:"spelers"`
+    Length  int      `json:"lengte"`
+}
+
+// Gemiddeld ratingverschil tussen de spelers van een ronde
+type RoundGap struct {
+    Round  int     `json:"ronde"`
+    AvgGap float64 `json:"gemiddeld_verschil"`
+}
+
+// Statistieken over alle gespeelde rondes
+type Stats struct {
+    Rounds          int        `json:"rondes"`
+    Games           int        `json:"partijen"`
+    BiggestUpset    *GameFact  `json:"grootste_upset,omitempty"`
+    HighestScore    *GameFact  `json:"hoogste_score,omitempty"`
+    LongestStreak   Streak     `json:"langste_winstreeks"`
+    Draws           int        `json:"gelijkspelen"`
+    DrawPercentage  float64    `json:"percentage_gelijk"`
+    FirstMoverWins  int        `json:"winst_eerste_speler"`
+    SecondMoverWins int        `json:"winst_tweede_speler"`
+    FirstMoverPct   float64    `json:"percentage_winst_eerste_speler"`
+    RatingGaps      []RoundGap `json:"ratingverschil_per_ronde"`
+}
+
+// Statistieken berekenen uit de uitslagen van alle rondes, met de ratings bij de start
+func buildStats(players []Player, allResults [][]Result) Stats {
+    ratings := startRatings(players)
+
+    stats := Stats{Rounds: len(allResults)}
+    streak := make(map[string]int)
+    streakPlayers := make(map[string]bool) // Spelers die al in LongestStreak staan
+    for r, results := range allResults {
+        gapTotal, gapGames := 0, 0
+        for _, result := range results {
+            if result.Player2 == "Bye" {
+                continue
+            }
+            stats.Games++
+            rating1, rating2 := ratings[result.Player1], ratings[result.Player2]
+            gapTotal += int(math.Abs(float64(rating1 - rating2)))
+            gapGames++
+
+            if result.Score1 == result.Score2 {
+                stats.Draws++
+                streak[result.Player1] = 0
+                streak[result.Player2] = 0
+                continue
+            }
+            fact := GameFact{Round: r + 1, Winner: result.Player1, Loser: result.Player2,
+                WinnerRating: rating1, LoserRating: rating2}
+            winScore, loseScore := result.Score1, result.Score2
+            if result.Score2 > result.Score1 {
+                stats.SecondMoverWins++
+                fact.Winner, fact.Loser = result.Player2, result.Player1
+                fact.WinnerRating, fact.LoserRating = rating2, rating1
+                winScore, loseScore = result.Score2, result.Score1
+            } else {
+                stats.FirstMoverWins++
+            }
+            fact.Score = fmt.Sprintf("%d-%d", winScore, loseScore)
+
+            upset := fact
+            upset.Value = fact.LoserRating - fact.WinnerRating
+            if upset.Value > 0 && (stats.BiggestUpset == nil || upset.Value > stats.BiggestUpset.Value) {
+                stats.BiggestUpset = &upset
+            }
+            high := fact
+            high.Value = winScore
+            if stats.HighestScore == nil || high.Value > stats.HighestScore.Value {
+                stats.HighestScore = &high
+            }
+
+            streak[fact.Winner]++
+            streak[fact.Loser] = 0
+            if streak[fact.Winner] > stats.LongestStreak.Length {
+                stats.LongestStreak = Streak{Players: []string{fact.Winner}, Length: streak[fact.Winner]}
+                streakPlayers = map[string]bool{fact.Winner: true}
+            } else if streak[fact.Winner] == stats.LongestStreak.Length && !streakPlayers[fact.Winner] {
+                // Een speler kan dezelfde langste reeks meer dan eens halen, maar staat er maar één keer in
+                stats.LongestStreak.Players = append(stats.LongestStreak.Players, fact.Winner)
+                streakPlayers[fact.Winner] = true
+            }
+        }
+        gap := RoundGap{Round: r + 1}
+        if gapGames > 0 {
+            gap.AvgGap = float64(gapTotal) / float64(gapGames)
+        }
+        stats.RatingGaps = append(stats.RatingGaps, gap)
+    }
+    if stats.Games > 0 {
+        stats.DrawPercentage = 100 * float64(stats.Draws) / float64(stats.Games)
+        stats.FirstMoverPct = 100 * float64(stats.FirstMoverWins) / float64(stats.Games)
+    }
+    return stats
+}
+
+// Statistiekenrapport wegschrijven als stats.html en stats.json
+func generateStats(stats Stats) error {
+    t, err := loadTemplate(nil, "stats.html")
+    if err != nil {
+        return err
+    }
+
+    file, err := os.Create("stats.html")
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+    if err := t.Execute(file, stats); err != nil {
+        return err
+    }
+
+    data, err := json.MarshalIndent(stats, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile("stats.json", data, 0644)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+    "embed"
+    "fmt"
+    "html/template"
+    "os"
+    "path/filepath"
+)
+
+// Standaard templates en CSS, meegecompileerd in het programma
+//go:embed templates/*.html templates/*.css
+var defaultTemplates embed.FS
+
+// Bestand uit de templatemap van de configuratie lezen, of anders de meegeleverde versie
+func readTemplateFile(name string) (string, error) {
+    if config.TemplateDir != "" {
+        data, err := os.ReadFile(filepath.Join(config.TemplateDir, name))
+        if err == nil {
+            return string(data), nil
+        } else if !os.IsNotExist(err) {
+            return "", err
+        }
+    }
+    data, err := defaultTemplates.ReadFile("templates/" + name)
+    return string(data), err
+}
+
+// Functies die in alle templates beschikbaar zijn
+func templateFuncs() template.FuncMap {
+    return template.FuncMap{
+        "add": func(a int, b int) int { return a + b },
+        "div": func(a float64, b int) float64 {
+            if b == 0 {
+                return 0 // Voorkomt deling door nul
+            }
+            return a / float64(b)
+        },
+        // Kans of verwachte score (0 tot 1) als percentage
+        "percent": func(f float64) string { return fmt.Sprintf("%.0f%%", 100*f) },
+        // CSS-bestand invoegen in een <style>-blok
+        "css": func(name string) (template.CSS, error) {
+            css, err := readTemplateFile(name)
+            return template.CSS(css), err
+        },
+        // Tekst uit de berichtencatalogus van de gekozen taal
+        "t": T,
+    }
+}
+
+// Template laden uit één of meer bestanden; extra functies vullen templateFuncs aan
+func loadTemplate(extra template.FuncMap, names ...string) (*template.Template, error) {
+    funcs := templateFuncs()
+    for name, fn := range extra {
+        funcs[name] = fn
+    }
+    root := template.New(names[0]).Funcs(funcs)
+    for i, name := range names {
+        text, err := readTemplateFile(name)
+        if err != nil {
+            return nil, err
+        }
+        t := root
+        if i > 0 {
+            t = root.New(name)
+        }
+        if _, err := t.Parse(text); err != nil {
+            return nil, err
+        }
+    }
+    return root, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+    "encoding/json"
+    "os"
+)
+
+// Versie van het toernooi.json formaat
+const toernooiVersie = 1
+
+// Toernooi is het gestructureerde bestandsformaat (toernooi.json) met de volledige geschiedenis
+type Toernooi struct {
+    Versie  int              `json:"versie"`
+    Spelers []ToernooiSpeler `json:"spelers"`
+    Rondes  []ToernooiRonde  `json:"rondes"`
+}
+
+// ToernooiSpeler bevat de gegevens van een speler bij de start van het toernooi
+type ToernooiSpeler struct {
+    Naam   string `json:"naam"`
+    Level  int    `json:"level"`
+    Rating int    `json:"rating"`
+}
+
+// ToernooiRonde bevat de uitslagen van een ronde en de stand daarna
+type ToernooiRonde struct {
+    Nummer    int          `json:"nummer"`
+    Uitslagen []Result     `json:"uitslagen"`
+    Stand     []StandRegel `json:"stand"`
+}
+
+// StandRegel is een regel uit de stand na een ronde
+type StandRegel struct {
+    Rank         int      `json:"rank"`
+    Naam         string   `json:"naam"`
+    Punten       int      `json:"punten"`
+    Matchscore   int      `json:"matchscore"`
+    RatOpp       float64  `json:"ratopp"`
+    RoundsPlayed int      `json:"gespeeld"`
+    Opponents    []string `json:"tegenstanders"`
+}
+
+// Spelers kopiëren zonder toernooistatistieken, als beginstand voor het herberekenen van rondes
+func resetPlayers(players []Player) []Player {
+    fresh := make([]Player, len(players))
+    for i, p := range players {
+        fresh[i] = Player{
+            Name:        p.Name,
+            Level:       p.Level,
+            Rating:      startRating(p),
+            Games:       p.Games,
+            Age:         p.Age,
+            RD:          p.RD,
+            Volatility:  p.Volatility,
+            Provisional: p.Provisional,
+            Opponents:   []string{},
+        }
+    }
+    return fresh
+}
+
+// Diepe kopie van de spelers, zodat latere updates een momentopname niet wijzigen
+func copyPlayers(players []Player) []Player {
+    copied := make([]Player, len(players))
+    for i, p := range players {
+        copied[i] = p
+        copied[i].Opponents = append([]string{}, p.Opponents...)
+        copied[i].RoundRatings = append([]int(nil), p.RoundRatings...)
+    }
+    return copied
+}
+
+// Alle rondes opnieuw afspelen vanaf de beginstand; geeft de gesorteerde stand na elke ronde terug.
+// Bij "per_round" krijgt elke speler na elke ronde de nieuwe rating, zodat RatOpp van latere rondes die gebruikt.
+func replayRounds(initial []Player, allResults [][]Result) [][]Player {
+    players := resetPlayers(initial)
+    state := ratingState(players, startRatings(players))
+    system := activeRatingSystem()
+    var standings [][]Player
+    for r, results := range allResults {
+        updatePlayers(players, results)
+        if config.Rating.Mode == "per_round" {
+            ratePeriod(state, allResults, []int{r}, system)
+            for i := range players {
+                players[i].Rating = state[players[i].Name].Rating
+            }
+        }
+        snapshot := copyPlayers(players)
+        sortPlayers(snapshot)
+        standings = append(standings, snapshot)
+    }
+    return standings
+}
+
+// Ratingstand na het afrekenen van de gegeven rondes: rating, partijen en bij Glicko-2 deviatie en volatiliteit.
+// Bij "batch" wordt pas na het toernooi afgerekend; dan is dit de stand bij de start.
+func currentRatingState(initial []Player, allResults [][]Result) map[string]Player {
+    players := resetPlayers(initial)
+    state := ratingState(players, startRatings(players))
+    if config.Rating.Mode == "per_round" {
+        system := activeRatingSystem()
+        for r := range allResults {
+            ratePeriod(state, allResults, []int{r}, system)
+        }
+    }
+    return state
+}
+
+// Huidige ratings van de spelers na het herberekenen van alle rondes overnemen (voor "per_round").
+// Partijen, deviatie en volatiliteit blijven de waarden bij de start; die staan in currentRatingState.
+func applyRoundRatings(players []Player, allResults [][]Result) {
+    state := currentRatingState(players, allResults)
+    for i := range players {
+        players[i].Rating = state[players[i].Name].Rating
+    }
+}
+
+// Toernooi opbouwen uit de beginspelers en de uitslagen van alle rondes
+func buildToernooi(initial []Player, allResults [][]Result) Toernooi {
+    t := Toernooi{Versie: toernooiVersie}
+    for _, p := range initial {
+        t.Spelers = append(t.Spelers, ToernooiSpeler{Naam: p.Name, Level: p.Level, Rating: p.Rating})
+    }
+    standings := replayRounds(initial, allResults)
+    for r, results := range allResults {
+        ronde := ToernooiRonde{Nummer: r + 1, Uitslagen: results}
+        for i, p := range standings[r] {
+            var ratOpp float64
+            if p.RoundsPlayed > 0 {
+                ratOpp = p.RatOppTotal / float64(p.RoundsPlayed)
+            }
+            ronde.Stand = append(ronde.Stand, StandRegel{
+                Rank:         i + 1,
+                Naam:         p.Name,
+                Punten:       p.Punten,
+                Matchscore:   p.Matchscore,
+                RatOpp:       ratOpp,
+                RoundsPlayed: p.RoundsPlayed,
+                Opponents:    p.Opponents,
+            })
+        }
+        t.Rondes = append(t.Rondes, ronde)
+    }
+    return t
+}
+
+func saveToernooi(filename string, t Toernooi) error {
+    data, err := json.MarshalIndent(t, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(filename, data, 0644)
+}
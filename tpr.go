@@ -0,0 +1,54 @@
+package main
+
+import "math"
+
+// FIDE-tabel: ratingverschil dp bij een score percentage p van 0.50 t/m 1.00 (per 0.01)
+var fideDP = []int{
+    0, 7, 14, 21, 29, 36, 43, 50, 57, 65,
+    72, 80, 87, 95, 102, 110, 117, 125, 133, 141,
+    149, 158, 166, 175, 184, 193, 202, 211, 220, 230,
+    240, 251, 262, 273, 284, 296, 309, 322, 336, 351,
+    366, 383, 401, 422, 444, 470, 501, 538, 589, 677,
+    800,
+}
+
+// Gemiddelde rating van de tegenstanders en de score in partijpunten (winst 1, remise 0.5), zonder byes
+func performanceInput(p Player) (avgOpp float64, score float64, games int) {
+    games = p.RoundsPlayed
+    if games == 0 {
+        return 0, 0, 0
+    }
+    avgOpp = p.RatOppTotal / float64(games)
+    score = float64(p.Punten-2*p.Byes) / 2
+    return avgOpp, score, games
+}
+
+// Tournament performance rating volgens de lineaire Elo-benadering: Ra + 400 * (W - L) / n
+func linearTPR(p Player) float64 {
+    avgOpp, score, games := performanceInput(p)
+    if games == 0 {
+        return 0
+    }
+    return math.Round(avgOpp + 400*(2*score-float64(games))/float64(games))
+}
+
+// Tournament performance rating volgens de FIDE dp-tabel: Ra + dp(p)
+func fideTPR(p Player) float64 {
+    avgOpp, score, games := performanceInput(p)
+    if games == 0 {
+        return 0
+    }
+    return math.Round(avgOpp + float64(fideDPFor(score/float64(games))))
+}
+
+// dp opzoeken voor een score percentage, afgerond op twee decimalen
+func fideDPFor(percentage float64) int {
+    idx := int(math.Round(math.Abs(percentage-0.5) * 100))
+    if idx >= len(fideDP) {
+        idx = len(fideDP) - 1
+    }
+    if percentage < 0.5 {
+        return -fideDP[idx]
+    }
+    return fideDP[idx]
+}